@@ -0,0 +1,101 @@
+package mono
+
+import (
+	"sort"
+	"strconv"
+)
+
+// BalanceIssueKind is the kind of inconsistency VerifyBalances finds.
+type BalanceIssueKind string
+
+const (
+	// BalanceGap means transactions are missing between two items, as the balance does not add up.
+	BalanceGap BalanceIssueKind = "gap"
+	// BalanceOutOfOrder means the newer item is listed after the older one.
+	BalanceOutOfOrder BalanceIssueKind = "out of order"
+	// BalanceDuplicate means the item is listed more than once.
+	BalanceDuplicate BalanceIssueKind = "duplicate"
+	// BalanceAccountMismatch means the latest item balance differs from the account balance.
+	BalanceAccountMismatch BalanceIssueKind = "account mismatch"
+)
+
+// BalanceIssue is the inconsistency between two statement items, or between the latest item and the account.
+type BalanceIssue struct {
+	Kind BalanceIssueKind
+	// Before and After are the items around the issue, the older one first.
+	// For duplicates, Before is the first occurrence. For the account mismatch, After is empty.
+	Before StatementItem
+	After  StatementItem
+	// Missing is the sum of amounts the gap or the account mismatch lacks.
+	Missing int64
+}
+
+// String describes the issue with IDs and times of the items around it.
+func (i BalanceIssue) String() string {
+	s := string(i.Kind) + " between " + describeItem(i.Before)
+
+	if i.Kind == BalanceAccountMismatch {
+		s = string(i.Kind) + " after " + describeItem(i.Before)
+	} else {
+		s += " and " + describeItem(i.After)
+	}
+
+	if i.Missing != 0 {
+		s += ", missing " + strconv.FormatInt(i.Missing, 10)
+	}
+
+	return s
+}
+
+// VerifyBalances checks that every balance in the statement follows from the previous one and the amount,
+// that is `prev.Balance + Amount == Balance`, and that the latest balance equals the account balance.
+// Items are expected the latest first, as Statements returns them.
+// The check is skipped for the account without ID.
+func VerifyBalances(account Account, items []StatementItem) []BalanceIssue {
+	issues := make([]BalanceIssue, 0)
+	chain := make([]StatementItem, 0, len(items))
+	first := make(map[string]StatementItem, len(items))
+
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+
+		if original, ok := first[item.ID]; ok {
+			issues = append(issues, BalanceIssue{Kind: BalanceDuplicate, Before: original, After: item})
+			continue
+		}
+
+		first[item.ID] = item
+
+		if i > 0 && items[i-1].Time < item.Time {
+			issues = append(issues, BalanceIssue{Kind: BalanceOutOfOrder, Before: items[i-1], After: item})
+		}
+
+		chain = append(chain, item)
+	}
+
+	sort.SliceStable(chain, func(i, j int) bool {
+		return chain[i].Time < chain[j].Time
+	})
+
+	for i := 1; i < len(chain); i++ {
+		prev, item := chain[i-1], chain[i]
+
+		if missing := item.Balance - item.Amount - prev.Balance; missing != 0 {
+			issues = append(issues, BalanceIssue{Kind: BalanceGap, Before: prev, After: item, Missing: missing})
+		}
+	}
+
+	if len(chain) > 0 && len(account.ID) > 0 {
+		latest := chain[len(chain)-1]
+
+		if missing := account.Balance - latest.Balance; missing != 0 {
+			issues = append(issues, BalanceIssue{Kind: BalanceAccountMismatch, Before: latest, Missing: missing})
+		}
+	}
+
+	return issues
+}
+
+func describeItem(item StatementItem) string {
+	return item.ID + " at " + item.Time.Time().UTC().Format("2006-01-02T15:04:05Z")
+}
@@ -0,0 +1,42 @@
+package mono_test
+
+import (
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestVerifyBalances(t *testing.T) {
+	items := []mono.StatementItem{
+		{ID: "c", Time: 300, Amount: -50, Balance: 900},
+		{ID: "b", Time: 200, Amount: -50, Balance: 950},
+		{ID: "a", Time: 100, Amount: 1000, Balance: 1000},
+	}
+
+	issues := mono.VerifyBalances(mono.Account{ID: "acc", Balance: 900}, items)
+	expectDeepEquals(t, issues, []mono.BalanceIssue{})
+}
+
+func TestVerifyBalances_Issues(t *testing.T) {
+	a := mono.StatementItem{ID: "a", Time: 100, Amount: 1000, Balance: 1000}
+	b := mono.StatementItem{ID: "b", Time: 200, Amount: -50, Balance: 950}
+	c := mono.StatementItem{ID: "c", Time: 300, Amount: -50, Balance: 880}
+	d := mono.StatementItem{ID: "d", Time: 400, Amount: -80, Balance: 800}
+
+	issues := mono.VerifyBalances(mono.Account{ID: "acc", Balance: 700}, []mono.StatementItem{d, b, c, b, a})
+	expectDeepEquals(t, issues, []mono.BalanceIssue{
+		{Kind: mono.BalanceOutOfOrder, Before: b, After: c},
+		{Kind: mono.BalanceDuplicate, Before: b, After: b},
+		{Kind: mono.BalanceGap, Before: b, After: c, Missing: -20},
+		{Kind: mono.BalanceAccountMismatch, Before: d, Missing: -100},
+	})
+
+	expectEquals(t, issues[2].String(), "gap between b at 1970-01-01T00:03:20Z and c at 1970-01-01T00:05:00Z, missing -20")
+	expectEquals(t, issues[3].String(), "account mismatch after d at 1970-01-01T00:06:40Z, missing -100")
+}
+
+func TestVerifyBalances_Empty(t *testing.T) {
+	expectDeepEquals(t, mono.VerifyBalances(mono.Account{ID: "acc", Balance: 5}, nil), []mono.BalanceIssue{})
+	expectDeepEquals(t, mono.VerifyBalances(mono.Account{}, []mono.StatementItem{{ID: "a", Balance: 5}}),
+		[]mono.BalanceIssue{})
+}
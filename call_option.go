@@ -0,0 +1,44 @@
+package mono
+
+import "time"
+
+// CallOption changes the single API call, unlike Option that configures the client.
+type CallOption func(*Call)
+
+// CallTimeout limits the call, including retries and waiting for the rate limit.
+// It only shortens the deadline of the context passed to the method.
+func CallTimeout(d time.Duration) CallOption {
+	return func(c *Call) {
+		c.timeout = d
+	}
+}
+
+// Header adds the header to the request. The option can be used more than once.
+func Header(key, value string) CallOption {
+	return func(c *Call) {
+		c.Header.Add(key, value)
+	}
+}
+
+// RequestID sets the `X-Request-Id` header, so the call can be found in logs of both sides.
+func RequestID(id string) CallOption {
+	return func(c *Call) {
+		c.Header.Set("X-Request-Id", id)
+	}
+}
+
+// CaptureResponse fills the response with what the bank answered once the call is done,
+// also when it fails. It keeps the raw body for audit, which the method itself decodes and drops.
+func CaptureResponse(dst *Response) CallOption {
+	return func(c *Call) {
+		c.capture = dst
+	}
+}
+
+// NoCache makes the call go to the bank even if the cached response is there.
+// The fresh response still replaces the cached one.
+func NoCache() CallOption {
+	return func(c *Call) {
+		c.NoCache = true
+	}
+}
@@ -0,0 +1,112 @@
+package mono_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestCallOptions_Header(t *testing.T) {
+	client := &seqclienttest{Resps: []*http.Response{okResponse(personalResponseBody)}}
+	personal := newPersonal(t, "token", mono.WithClient(client))
+
+	_, err := personal.ClientInfo(context.Background(),
+		mono.Header("X-Trace", "a"),
+		mono.Header("X-Trace", "b"),
+		mono.RequestID("request-1"),
+	)
+	expectNoError(t, err)
+
+	req := client.Reqs[0]
+	expectDeepEquals(t, req.Header["X-Trace"], []string{"a", "b"})
+	expectEquals(t, req.Header.Get("X-Request-Id"), "request-1")
+	expectEquals(t, req.Header.Get("X-Token"), "token")
+}
+
+func TestCallOptions_HeaderSeenByMiddleware(t *testing.T) {
+	client := &seqclienttest{Resps: []*http.Response{okResponse(`{}`)}}
+
+	var requestID string
+
+	middleware := func(next mono.Handler) mono.Handler {
+		return func(ctx context.Context, call *mono.Call) error {
+			requestID = call.Header.Get("X-Request-Id")
+			return next(ctx, call)
+		}
+	}
+
+	personal := newPersonal(t, "token", mono.WithClient(client), mono.WithMiddleware(middleware))
+
+	expectNoError(t, personal.SetWebhook(context.Background(), "https://domain/webhook", mono.RequestID("request-2")))
+	expectEquals(t, requestID, "request-2")
+	expectEquals(t, client.Reqs[0].Header.Get("X-Request-Id"), "request-2")
+}
+
+func TestCallTimeout(t *testing.T) {
+	var deadline time.Time
+
+	client := clientFunc(func(req *http.Request) (*http.Response, error) {
+		deadline, _ = req.Context().Deadline()
+
+		<-req.Context().Done()
+
+		return nil, req.Context().Err()
+	})
+
+	public := mono.NewPublic(mono.WithClient(client))
+	started := time.Now()
+
+	_, err := public.Currency(context.Background(), mono.CallTimeout(10*time.Millisecond))
+	expectTrue(t, errors.Is(err, context.DeadlineExceeded))
+	expectTrue(t, deadline.Sub(started) < time.Second)
+}
+
+type clientFunc func(req *http.Request) (*http.Response, error)
+
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCaptureResponse(t *testing.T) {
+	resp := okResponse(`[{"currencyCodeA": 840}]`)
+	resp.Header = http.Header{"X-Bank": []string{"mono"}}
+
+	client := &seqclienttest{Resps: []*http.Response{resp}}
+	public := mono.NewPublic(mono.WithClient(client))
+
+	var captured mono.Response
+
+	rates, err := public.Currency(context.Background(), mono.CaptureResponse(&captured))
+	expectNoError(t, err)
+	expectEquals(t, rates[0].CurrencyCodeAISO4217, 840)
+	expectEquals(t, captured.StatusCode, http.StatusOK)
+	expectEquals(t, captured.Header.Get("X-Bank"), "mono")
+	expectEquals(t, string(captured.Body), `[{"currencyCodeA": 840}]`)
+	expectEquals(t, captured.Attempts, 1)
+	expectTrue(t, captured.Latency >= 0)
+}
+
+func TestCaptureResponse_Failed(t *testing.T) {
+	forbidden := func() *http.Response {
+		resp := okResponse(`{"errorDescription": "Unknown 'X-Token'"}`)
+		resp.StatusCode = http.StatusForbidden
+
+		return resp
+	}
+
+	client := &seqclienttest{Resps: []*http.Response{forbidden(), forbidden()}}
+	refresh := func(context.Context) error { return nil }
+	personal := newPersonal(t, "token", mono.WithClient(client), mono.WithTokenRefresh(refresh))
+
+	var captured mono.Response
+
+	_, err := personal.ClientInfo(context.Background(), mono.CaptureResponse(&captured))
+	expectError(t, err, "mono error: Unknown 'X-Token'")
+	expectEquals(t, captured.StatusCode, http.StatusForbidden)
+	expectEquals(t, string(captured.Body), `{"errorDescription": "Unknown 'X-Token'"}`)
+	expectEquals(t, captured.Attempts, 2)
+}
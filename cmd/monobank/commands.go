@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/internal/paging"
+	"github.com/kudrykv/go-monobank-api/internal/timer"
+)
+
+func (a *app) rates(ctx context.Context) error {
+	currencies, err := mono.NewPublic(a.options()...).Currency(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := table{value: currencies, header: []string{"A", "B", "DATE", "BUY", "SELL", "CROSS"}}
+
+	for _, c := range currencies {
+		t.rows = append(t.rows, []string{
+			strconv.Itoa(c.CurrencyCodeAISO4217),
+			strconv.Itoa(c.CurrencyCodeBISO4217),
+			formatTime(c.Date),
+			formatRate(c.RateBuy),
+			formatRate(c.RateSell),
+			formatRate(c.RateCross),
+		})
+	}
+
+	return render(a.stdout, a.format, t)
+}
+
+func (a *app) info(ctx context.Context) error {
+	personal, err := a.personal()
+	if err != nil {
+		return err
+	}
+
+	info, err := personal.ClientInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.format == formatTable {
+		fmt.Fprintf(a.stdout, "Name: %s\nWebhook: %s\n\n", info.Name, info.WebHookURL)
+	}
+
+	t := table{value: info, header: []string{"ID", "CURRENCY", "BALANCE", "CREDIT LIMIT", "CASHBACK"}}
+
+	for _, acc := range info.Accounts {
+		t.rows = append(t.rows, []string{
+			acc.ID,
+			strconv.Itoa(acc.CurrencyCodeISO4217),
+			mono.FormatAmount(acc.Balance, acc.CurrencyCodeISO4217),
+			mono.FormatAmount(acc.CreditLimit, acc.CurrencyCodeISO4217),
+			string(acc.CashbackType),
+		})
+	}
+
+	return render(a.stdout, a.format, t)
+}
+
+func (a *app) statements(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("statements", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	account := fs.String("account", "0", "account ID, 0 for the default account")
+	fromFlag := fs.String("from", "", "start of the period: 2006-01-02 or RFC 3339, 30 days ago by default")
+	toFlag := fs.String("to", "", "end of the period: 2006-01-02 or RFC 3339, now by default")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	to, err := parseTime(*toFlag, time.Now())
+	if err != nil {
+		return err
+	}
+
+	from, err := parseTime(*fromFlag, to.AddDate(0, 0, -30))
+	if err != nil {
+		return err
+	}
+
+	personal, err := a.personal()
+	if err != nil {
+		return err
+	}
+
+	info, err := personal.ClientInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	items, err := fetchStatements(ctx, personal, *account, from, to, a.wait)
+	if err != nil {
+		return err
+	}
+
+	return render(a.stdout, a.format, statementTable(items, accountCurrency(info.Accounts, *account)))
+}
+
+// fetchStatements gets transactions for the period of any length,
+// waiting between calls to stay within the rate limit.
+func fetchStatements(
+	ctx context.Context, personal mono.Personal, account string, from, to time.Time,
+	wait func(context.Context, time.Duration) error,
+) ([]mono.StatementItem, error) {
+	calls := 0
+
+	return paging.Fetch(ctx, from, to, func(ctx context.Context, from, to time.Time) ([]mono.StatementItem, error) {
+		if calls > 0 {
+			if err := wait(ctx, mono.PersonalRateLimit); err != nil {
+				return nil, err
+			}
+		}
+
+		calls++
+
+		return personal.Statements(ctx, account, from, to)
+	})
+}
+
+func (a *app) webhook(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("webhook command is required: set, get or clear")
+	}
+
+	personal, err := a.personal()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "set":
+		if len(args) != 2 {
+			return errors.New("usage: webhook set <url>")
+		}
+
+		return personal.SetWebhook(ctx, args[1])
+
+	case "get":
+		wh, err := personal.Webhook(ctx)
+		if err != nil {
+			return err
+		}
+
+		return render(a.stdout, a.format, table{
+			value:  map[string]string{"webHookUrl": wh},
+			header: []string{"WEBHOOK"},
+			rows:   [][]string{{wh}},
+		})
+
+	case "clear":
+		return personal.ClearWebhook(ctx)
+	}
+
+	return errors.New("unknown webhook command " + args[0])
+}
+
+func (a *app) listen(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("listen", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	path := fs.String("path", "/", "path to serve the webhook on")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	whch, handler := mono.ListenForWebhooks(ctx)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(*path, handler)
+
+	srv := &http.Server{Addr: *addr, Handler: mux}
+	errch := make(chan error, 1)
+
+	go func() {
+		errch <- srv.ListenAndServe()
+	}()
+
+	fmt.Fprintln(a.stderr, "listening on "+*addr+*path)
+
+	printWebhook := a.webhookPrinter()
+
+	for {
+		select {
+		case err := <-errch:
+			return err
+
+		case <-ctx.Done():
+			return srv.Shutdown(context.Background())
+
+		case wh := <-whch:
+			if err := printWebhook(wh); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// webhookPrinter prints webhooks one by one as they arrive.
+// Table and CSV get the header once, JSON gets one object per line.
+// Webhooks do not tell the account currency, so account amounts get two decimals, like every account of the bank.
+func (a *app) webhookPrinter() func(mono.WebhookData) error {
+	header := append([]string{"ACCOUNT"}, statementHeader()...)
+
+	switch a.format {
+	case formatJSON:
+		enc := json.NewEncoder(a.stdout)
+		return func(wh mono.WebhookData) error { return enc.Encode(wh) }
+
+	case formatCSV:
+		cw := csv.NewWriter(a.stdout)
+		headerErr := cw.Write(header)
+
+		return func(wh mono.WebhookData) error {
+			if headerErr != nil {
+				return headerErr
+			}
+
+			_ = cw.Write(append([]string{wh.Data.AccountID}, statementRow(wh.Data.StatementItem, 0)...))
+			cw.Flush()
+
+			return cw.Error()
+		}
+	}
+
+	fmt.Fprintln(a.stdout, strings.Join(header, "\t"))
+
+	return func(wh mono.WebhookData) error {
+		row := append([]string{wh.Data.AccountID}, statementRow(wh.Data.StatementItem, 0)...)
+		_, err := fmt.Fprintln(a.stdout, strings.Join(row, "\t"))
+
+		return err
+	}
+}
+
+func (a *app) wait(ctx context.Context, d time.Duration) error {
+	fmt.Fprintf(a.stderr, "waiting %s for the rate limit\n", d)
+
+	return timer.Sleep(ctx, d)
+}
+
+func statementHeader() []string {
+	return []string{"TIME", "ID", "DESCRIPTION", "MCC", "AMOUNT", "OPERATION AMOUNT", "CURRENCY", "BALANCE", "HOLD"}
+}
+
+// statementRow formats amounts in minor units of their currencies.
+// The amount and the balance are in the account currency; the unknown zero one formats them with two decimals.
+func statementRow(item mono.StatementItem, currency int) []string {
+	return []string{
+		formatTime(item.Time),
+		item.ID,
+		item.Description,
+		strconv.Itoa(item.MCC),
+		mono.FormatAmount(item.Amount, currency),
+		mono.FormatAmount(item.OperationAmount, item.CurrencyCodeISO4217),
+		strconv.Itoa(item.CurrencyCodeISO4217),
+		mono.FormatAmount(item.Balance, currency),
+		strconv.FormatBool(item.Hold),
+	}
+}
+
+// accountCurrency returns the currency of the account, where 0 is the default account, or zero when it is unknown.
+func accountCurrency(accounts []mono.Account, account string) int {
+	for i, acc := range accounts {
+		if acc.ID == account || (account == "0" && i == 0) {
+			return acc.CurrencyCodeISO4217
+		}
+	}
+
+	return 0
+}
+
+func statementTable(items []mono.StatementItem, currency int) table {
+	t := table{value: items, header: statementHeader()}
+
+	if items == nil {
+		t.value = []mono.StatementItem{}
+	}
+
+	for _, item := range items {
+		t.rows = append(t.rows, statementRow(item, currency))
+	}
+
+	return t
+}
+
+func parseTime(value string, def time.Time) (time.Time, error) {
+	if len(value) == 0 {
+		return def, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("time must be 2006-01-02 or RFC 3339, got " + value)
+	}
+
+	return t, nil
+}
+
+func formatTime(t mono.Time) string {
+	return t.Time().Format(time.RFC3339)
+}
+
+func formatRate(rate float64) string {
+	if rate == 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
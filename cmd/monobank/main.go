@@ -0,0 +1,113 @@
+// Command monobank is the command-line client for the Monobank API.
+//
+// Usage:
+//
+//	monobank [flags] <command> [command flags]
+//
+// Commands:
+//
+//	rates                                    list currency rates
+//	info                                     show the client and accounts
+//	statements -account X -from D [-to D]    list transactions, splitting long periods into allowed windows
+//	webhook set <url> | get | clear          manage the webhook
+//	listen [-addr :8080] [-path /]           serve the webhook and print incoming transactions
+//
+// Personal commands read the token from MONOBANK_TOKEN, from the file given with -token-file,
+// or prompt for it.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+const tokenEnv = "MONOBANK_TOKEN"
+
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	format    string
+	tokenFile string
+	domain    string
+}
+
+func main() {
+	a := app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	if err := a.run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "monobank: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("monobank", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.format, "format", formatTable, "output format: table, json or csv")
+	fs.StringVar(&a.tokenFile, "token-file", "", "file to read the personal token from")
+	fs.StringVar(&a.domain, "domain", mono.DefaultDomain, "API domain")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := checkFormat(a.format); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("command is required")
+	}
+
+	cmd, rest := fs.Arg(0), fs.Args()[1:]
+
+	switch cmd {
+	case "rates":
+		return a.rates(ctx)
+	case "info":
+		return a.info(ctx)
+	case "statements":
+		return a.statements(ctx, rest)
+	case "webhook":
+		return a.webhook(ctx, rest)
+	case "listen":
+		return a.listen(ctx, rest)
+	}
+
+	return errors.New("unknown command " + cmd)
+}
+
+func (a *app) options() []mono.Option {
+	return []mono.Option{mono.WithDomain(a.domain)}
+}
+
+func (a *app) personal() (mono.Personal, error) {
+	token, err := readToken(a.getenv(tokenEnv), a.tokenFile, a.stdin, a.stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	return mono.NewPersonal(token, a.options()...)
+}
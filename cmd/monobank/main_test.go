@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+func newTestApp(srv *monotest.Server, token string) (*app, *bytes.Buffer) {
+	stdout := &bytes.Buffer{}
+
+	return &app{
+		stdin:  strings.NewReader(""),
+		stdout: stdout,
+		stderr: ioutil.Discard,
+		getenv: func(key string) string {
+			if key == tokenEnv {
+				return token
+			}
+
+			return ""
+		},
+	}, stdout
+}
+
+func TestRates(t *testing.T) {
+	srv := monotest.NewServer()
+	defer srv.Close()
+
+	srv.SetCurrencies(mono.CurrencyInfo{CurrencyCodeAISO4217: 840, CurrencyCodeBISO4217: 980, Date: 1, RateBuy: 27.5})
+
+	a, stdout := newTestApp(srv, "")
+	if err := a.run(context.Background(), []string{"-domain", srv.URL, "-format", "csv", "rates"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "A,B,DATE,BUY,SELL,CROSS\n840,980," + time.Unix(1, 0).Format(time.RFC3339) + ",27.5,,\n"
+	if stdout.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", stdout)
+	}
+}
+
+func TestInfoAndWebhook(t *testing.T) {
+	srv := monotest.NewServer()
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Name: "John", Accounts: []mono.Account{{ID: "acc", Balance: -1050}}})
+
+	a, stdout := newTestApp(srv, "token")
+	ctx := context.Background()
+
+	if err := a.run(ctx, []string{"-domain", srv.URL, "info"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(stdout.String(), "Name: John") || !strings.Contains(stdout.String(), "-10.50") {
+		t.Fatalf("Unexpected output:\n%s", stdout)
+	}
+
+	if err := a.run(ctx, []string{"-domain", srv.URL, "webhook", "set", "http://insecure"}); err == nil {
+		t.Fatal("Expected the insecure webhook to be rejected")
+	}
+
+	if err := a.run(ctx, []string{"-domain", srv.URL, "webhook", "clear"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.run(ctx, []string{"-domain", srv.URL, "-format", "json", "webhook", "get"}); err == nil {
+		t.Fatal("Expected client info to be rate limited")
+	}
+
+	if err := a.run(ctx, []string{"-domain", srv.URL, "-format", "yaml", "info"}); err == nil {
+		t.Fatal("Expected unknown format to fail")
+	}
+}
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) wait(_ context.Context, d time.Duration) error {
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func TestFetchStatements_Windows(t *testing.T) {
+	clk := &clock{now: time.Now()}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	gen := monotest.NewGenerator(1)
+
+	info, err := gen.Populate(srv, "token", 1, 300, clk.Now().AddDate(0, 0, -100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	personal, err := mono.NewPersonal("token", srv.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from, to := clk.Now().AddDate(0, 0, -100), clk.Now().AddDate(0, 0, 100)
+
+	items, err := fetchStatements(context.Background(), personal, "0", from, to, clk.wait)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 300 || items[0].Balance != info.Accounts[0].Balance {
+		t.Fatalf("Expected all 300 items the latest first, got %d", len(items))
+	}
+}
+
+func TestFetchStatements_Pages(t *testing.T) {
+	clk := &clock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "acc"}}})
+
+	for i := 0; i < 1200; i++ {
+		at := clk.Now().Add(-time.Duration(i) * time.Minute)
+
+		item := mono.StatementItem{ID: strconv.Itoa(i), Time: mono.Time(at.Unix())}
+		if err := srv.AddStatements("token", "acc", item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	personal, err := mono.NewPersonal("token", srv.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := clk.Now().AddDate(0, 0, -1)
+	to := clk.Now()
+
+	items, err := fetchStatements(context.Background(), personal, "acc", from, to, clk.wait)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1200 || items[0].ID != "0" || items[1199].ID != "1199" {
+		t.Fatalf("Expected all 1200 items, got %d", len(items))
+	}
+
+	if !clk.Now().Equal(to.Add(2 * mono.PersonalRateLimit)) {
+		t.Fatalf("Expected to wait twice between three pages, waited %s", clk.Now().Sub(to))
+	}
+}
+
+func TestStatementRow_Currencies(t *testing.T) {
+	item := mono.StatementItem{Amount: -25050, OperationAmount: -10000, CurrencyCodeISO4217: 392, Balance: 100000}
+
+	row := statementRow(item, 980)
+	if row[4] != "-250.50" || row[5] != "-10000" || row[7] != "1000.00" {
+		t.Fatalf("Unexpected amounts: %v", row[4:8])
+	}
+
+	accounts := []mono.Account{{ID: "uah", CurrencyCodeISO4217: 980}, {ID: "usd", CurrencyCodeISO4217: 840}}
+	if accountCurrency(accounts, "0") != 980 || accountCurrency(accounts, "usd") != 840 ||
+		accountCurrency(accounts, "x") != 0 {
+		t.Fatal("Unexpected account currencies")
+	}
+}
+
+func TestStatements_BadTime(t *testing.T) {
+	srv := monotest.NewServer()
+	defer srv.Close()
+
+	a, stdout := newTestApp(srv, "token")
+
+	if err := a.run(context.Background(), []string{"-domain", srv.URL, "statements", "-from", "bad"}); err == nil {
+		t.Fatal("Expected bad time to fail")
+	}
+
+	if stdout.Len() != 0 {
+		t.Fatalf("Expected no output, got %s", stdout)
+	}
+}
+
+func TestReadToken(t *testing.T) {
+	token, err := readToken(" env ", "", nil, nil)
+	if err != nil || token != "env" {
+		t.Fatalf("Unexpected token %q, err %v", token, err)
+	}
+
+	file, err := ioutil.TempFile("", "monobank")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString("from-file\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err = readToken("", file.Name(), nil, nil); err != nil || token != "from-file" {
+		t.Fatalf("Unexpected token %q, err %v", token, err)
+	}
+
+	prompt := &bytes.Buffer{}
+
+	if token, err = readToken("", "", strings.NewReader("typed\n"), prompt); err != nil || token != "typed" {
+		t.Fatalf("Unexpected token %q, err %v", token, err)
+	}
+
+	if prompt.String() != "Monobank token: " {
+		t.Fatalf("Unexpected prompt %q", prompt)
+	}
+
+	if _, err = readToken("", "", strings.NewReader(""), prompt); err == nil || err.Error() != "token is required" {
+		t.Fatalf("Unexpected error %v", err)
+	}
+}
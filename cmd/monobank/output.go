@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	}
+
+	return errors.New("unknown format " + format)
+}
+
+// table is what the command prints: the value for JSON, and the header with rows for table and CSV.
+type table struct {
+	value  interface{}
+	header []string
+	rows   [][]string
+}
+
+func render(w io.Writer, format string, t table) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(t.value)
+
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.header); err != nil {
+			return err
+		}
+
+		if err := cw.WriteAll(t.rows); err != nil {
+			return err
+		}
+
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := io.WriteString(tw, strings.Join(t.header, "\t")+"\n"); err != nil {
+		return err
+	}
+
+	for _, row := range t.rows {
+		if _, err := io.WriteString(tw, strings.Join(row, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
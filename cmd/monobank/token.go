@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// readToken takes the token from the environment value, then from the file, and prompts for it otherwise.
+func readToken(env, file string, in io.Reader, prompt io.Writer) (string, error) {
+	if token := strings.TrimSpace(env); len(token) > 0 {
+		return token, nil
+	}
+
+	if len(file) > 0 {
+		bts, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %v", err)
+		}
+
+		if token := strings.TrimSpace(string(bts)); len(token) > 0 {
+			return token, nil
+		}
+
+		return "", errors.New("token file is empty")
+	}
+
+	fmt.Fprint(prompt, "Monobank token: ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read token: %v", err)
+	}
+
+	if token := strings.TrimSpace(line); len(token) > 0 {
+		return token, nil
+	}
+
+	return "", errors.New("token is required")
+}
@@ -1,5 +1,7 @@
 package mono
 
+import "time"
+
 // Cashback is the enum of allowed cashback types.
 type Cashback string
 
@@ -12,6 +14,23 @@ const (
 	// It equals to 31 days + 1 hour.
 	MaxAllowedDuration = 2682000
 
+	// PersonalRateLimit is how often the bank allows to call client info and statements with the same token.
+	PersonalRateLimit = 60 * time.Second
+
+	// CurrencyRateLimit is how often the bank allows to call currency rates.
+	CurrencyRateLimit = 5 * time.Minute
+
+	// MaxStatementItems is the most items the bank returns for the single statement call.
+	// The full page means the period may hold more items, up to the time of the last one.
+	MaxStatementItems = 500
+
+	// MaxWebhookURLLength is the maximum length of the webhook URL the library accepts.
+	MaxWebhookURLLength = 2048
+
+	// DefaultMaxResponseSize is the largest response body the client reads by default, 10 MiB.
+	// The full statement page of MaxStatementItems takes a few hundred kilobytes.
+	DefaultMaxResponseSize = 10 << 20
+
 	// CashbackNone tells there is no cashback.
 	CashbackNone Cashback = "None"
 	// CashbackUAH tells the cashback is in UAH.
@@ -1,6 +1,9 @@
 package mono
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type core struct {
 	domain       string
@@ -17,6 +20,10 @@ func (c *core) setClient(client HTTPClient) {
 	c.client = client
 }
 
+func (c *core) setMarshaller(m Marshaller) {
+	c.marshaller = m
+}
+
 func (c *core) setUnmarshaller(u Unmarshaller) {
 	c.unmarshaller = u
 }
@@ -25,13 +32,40 @@ func (c *core) setWebhookBufferSize(size uint32) {
 	c.whBufferSize = size
 }
 
+func (c *core) addMiddlewares(middlewares []Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+func (c *core) setLogger(logger Logger, bodies bool) {
+	c.logger = logger
+	c.logBodies = bodies
+}
+
+func (c *core) setMetrics(metrics Metrics) {
+	c.metrics = metrics
+}
+
+func (c *core) setTracer(tracer Tracer) {
+	c.tracer = tracer
+}
+
+func (c *core) setTokenRefresh(refresh func(ctx context.Context) error) {
+	c.refresh = refresh
+}
+
+func (c *core) setMaxResponseSize(size int64) {
+	c.maxResponseSize = size
+}
+
 func newCore(opts ...Option) core {
 	c := core{
 		domain:       DefaultDomain,
 		whBufferSize: 100,
 		tinyClient: tinyClient{
-			client:       &http.Client{},
-			unmarshaller: unmarshaller{},
+			client:          &http.Client{},
+			marshaller:      marshaller{},
+			unmarshaller:    unmarshaller{},
+			maxResponseSize: DefaultMaxResponseSize,
 		},
 	}
 
@@ -22,6 +22,15 @@ func TestCore_setClient(t *testing.T) {
 	}
 }
 
+func TestCore_setMarshaller(t *testing.T) {
+	mhs := &marshtest{}
+	c := newCore(WithMarshaller(mhs))
+
+	if c.marshaller != mhs {
+		t.Fatal("expected marshaller to be custom")
+	}
+}
+
 func TestCore_setUnmarshaller(t *testing.T) {
 	umhs := &unmtest{}
 	c := newCore(WithUnmarshaller(umhs))
@@ -39,6 +48,23 @@ func TestCore_setWebhookBufferSize(t *testing.T) {
 	}
 }
 
+func TestCore_addMiddlewares(t *testing.T) {
+	noop := func(next Handler) Handler { return next }
+	c := newCore(WithMiddleware(noop, noop), WithMiddleware(noop))
+
+	if len(c.middlewares) != 3 {
+		t.Fatal("expected middlewares to be appended")
+	}
+}
+
+func TestCore_setMaxResponseSize(t *testing.T) {
+	c := newCore(WithMaxResponseSize(1024))
+
+	if c.maxResponseSize != 1024 {
+		t.Fatal("expected max response size to be custom")
+	}
+}
+
 func TestCoreDefaults(t *testing.T) {
 	c := newCore()
 
@@ -51,6 +77,11 @@ func TestCoreDefaults(t *testing.T) {
 		t.Error("expected client to be a http.Client as default")
 	}
 
+	msh := marshaller{}
+	if c.marshaller != msh {
+		t.Error("expected default marshaller, got else")
+	}
+
 	umsh := unmarshaller{}
 	if c.unmarshaller != umsh {
 		t.Error("expected default unmarshaller, got else")
@@ -59,4 +90,8 @@ func TestCoreDefaults(t *testing.T) {
 	if c.whBufferSize != 100 {
 		t.Error("expected default wh buffer size, got else")
 	}
+
+	if c.maxResponseSize != DefaultMaxResponseSize {
+		t.Error("expected default max response size, got else")
+	}
 }
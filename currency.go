@@ -0,0 +1,107 @@
+package mono
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CurrencyAlpha returns the ISO 4217 alphabetic code for the numeric one, like `UAH` for 980.
+// Unknown codes are returned as numbers.
+func CurrencyAlpha(code int) string {
+	for _, c := range currencies() {
+		if c.code == code {
+			return c.alpha
+		}
+	}
+
+	return strconv.Itoa(code)
+}
+
+// CurrencyCode returns the ISO 4217 numeric code for the alphabetic one, like 980 for `UAH`.
+// It returns false for unknown codes.
+func CurrencyCode(alpha string) (int, bool) {
+	for _, c := range currencies() {
+		if c.alpha == alpha {
+			return c.code, true
+		}
+	}
+
+	return 0, false
+}
+
+type currency struct {
+	code  int
+	alpha string
+}
+
+func currencies() []currency {
+	return []currency{
+		{980, "UAH"},
+		{840, "USD"},
+		{978, "EUR"},
+		{826, "GBP"},
+		{985, "PLN"},
+		{203, "CZK"},
+		{348, "HUF"},
+		{946, "RON"},
+		{756, "CHF"},
+		{752, "SEK"},
+		{578, "NOK"},
+		{208, "DKK"},
+		{124, "CAD"},
+		{36, "AUD"},
+		{392, "JPY"},
+		{156, "CNY"},
+		{410, "KRW"},
+		{949, "TRY"},
+		{376, "ILS"},
+		{784, "AED"},
+		{933, "BYN"},
+		{398, "KZT"},
+		{981, "GEL"},
+		{498, "MDL"},
+		{51, "AMD"},
+		{944, "AZN"},
+		{48, "BHD"},
+		{414, "KWD"},
+		{512, "OMR"},
+		{788, "TND"},
+	}
+}
+
+// CurrencyMinorUnits returns the number of digits after the decimal separator
+// the currency with ISO 4217 numeric code has. Amounts in the API are in these minor units.
+// Most currencies have 2 digits, which is also the default for unknown codes.
+func CurrencyMinorUnits(code int) int {
+	switch code {
+	case 392, 410, 952, 704, 152:
+		return 0
+	case 48, 414, 512, 788, 368, 434, 400:
+		return 3
+	}
+
+	return 2
+}
+
+// FormatAmount formats the amount in minor units as the decimal number in the currency's major units.
+// For example, -95000 in UAH is "-950.00".
+func FormatAmount(amount int64, currency int) string {
+	minor := CurrencyMinorUnits(currency)
+
+	sign, abs := "", uint64(amount)
+	if amount < 0 {
+		sign, abs = "-", uint64(-amount)
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+
+	if minor == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= minor {
+		digits = strings.Repeat("0", minor-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-minor] + "." + digits[len(digits)-minor:]
+}
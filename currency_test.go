@@ -0,0 +1,42 @@
+package mono_test
+
+import (
+	"math"
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestCurrencyAlpha(t *testing.T) {
+	expectEquals(t, mono.CurrencyAlpha(980), "UAH")
+	expectEquals(t, mono.CurrencyAlpha(840), "USD")
+	expectEquals(t, mono.CurrencyAlpha(1), "1")
+}
+
+func TestCurrencyCode(t *testing.T) {
+	code, ok := mono.CurrencyCode("UAH")
+	expectTrue(t, ok)
+	expectEquals(t, code, 980)
+
+	_, ok = mono.CurrencyCode("XXX")
+	expectTrue(t, !ok)
+}
+
+func TestCurrencyMinorUnits(t *testing.T) {
+	expectEquals(t, mono.CurrencyMinorUnits(980), 2)
+	expectEquals(t, mono.CurrencyMinorUnits(392), 0)
+	expectEquals(t, mono.CurrencyMinorUnits(348), 2)
+	expectEquals(t, mono.CurrencyMinorUnits(48), 3)
+	expectEquals(t, mono.CurrencyMinorUnits(1), 2)
+}
+
+func TestFormatAmount(t *testing.T) {
+	expectEquals(t, mono.FormatAmount(-95000, 980), "-950.00")
+	expectEquals(t, mono.FormatAmount(5, 980), "0.05")
+	expectEquals(t, mono.FormatAmount(-5, 980), "-0.05")
+	expectEquals(t, mono.FormatAmount(0, 980), "0.00")
+	expectEquals(t, mono.FormatAmount(1234, 392), "1234")
+	expectEquals(t, mono.FormatAmount(123456, 348), "1234.56")
+	expectEquals(t, mono.FormatAmount(-1234, 48), "-1.234")
+	expectEquals(t, mono.FormatAmount(math.MinInt64, 980), "-92233720368547758.08")
+}
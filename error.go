@@ -0,0 +1,74 @@
+package mono
+
+// ErrorKind tells at which step the request failed.
+type ErrorKind string
+
+const (
+	// ErrorKindToken means the token source failed.
+	ErrorKindToken ErrorKind = "token"
+	// ErrorKindMarshal means the request body could not be encoded.
+	ErrorKindMarshal ErrorKind = "marshal"
+	// ErrorKindRequest means the HTTP request could not be created.
+	ErrorKindRequest ErrorKind = "request"
+	// ErrorKindTransport means the HTTP client failed to make the request.
+	ErrorKindTransport ErrorKind = "transport"
+	// ErrorKindRead means the response body could not be read.
+	ErrorKindRead ErrorKind = "read"
+	// ErrorKindClose means the response body could not be closed.
+	ErrorKindClose ErrorKind = "close"
+	// ErrorKindUnmarshal means the response body could not be decoded.
+	ErrorKindUnmarshal ErrorKind = "unmarshal"
+	// ErrorKindAPI means the bank answered with the error.
+	ErrorKindAPI ErrorKind = "api"
+)
+
+// Error is the error of the API call.
+// Its text is the same as before the error was typed, so the existing checks on messages keep working.
+type Error struct {
+	Kind ErrorKind
+	// StatusCode is the response status, zero when there was no response.
+	StatusCode int
+	// Description is the error description from the bank for ErrorKindAPI.
+	Description string
+	// Err is the underlying error for other kinds.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Kind == ErrorKindAPI {
+		return "mono error: " + e.Description
+	}
+
+	msg := "failed to " + e.action()
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) action() string {
+	switch e.Kind {
+	case ErrorKindToken:
+		return "get token"
+	case ErrorKindMarshal:
+		return "marshal body"
+	case ErrorKindRequest:
+		return "create request"
+	case ErrorKindTransport:
+		return "make request"
+	case ErrorKindRead:
+		return "read body"
+	case ErrorKindClose:
+		return "close the body"
+	case ErrorKindUnmarshal:
+		return "unmarshal body"
+	default:
+		return string(e.Kind)
+	}
+}
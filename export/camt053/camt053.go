@@ -0,0 +1,274 @@
+// Package camt053 writes statements as ISO 20022 camt.053.001.02 bank-to-customer statements.
+//
+// The opening balance is derived from the `Balance` field of the earliest item,
+// and the closing balance adds booked entries to it. Holds become pending entries that do not count towards it.
+// Counterparty IBANs, EDRPOU codes and comments,
+// when the bank provides them, become related parties and remittance information.
+package camt053
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+const (
+	// Namespace is the XML namespace of the written documents.
+	Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+	// MonobankBIC is the BIC of Universal Bank, which services Monobank accounts.
+	MonobankBIC = "UNJSUAUKXXX"
+
+	maxText = 140
+)
+
+// Option allows to change default values for the writer.
+type Option func(*config)
+
+// WithMessageID sets the message and statement identifier. Default is derived from the account and the time.
+func WithMessageID(id string) Option {
+	return func(c *config) {
+		c.id = id
+	}
+}
+
+// WithCreationTime sets the creation time of the document.
+// Default is the current time. Set it to get the reproducible output.
+func WithCreationTime(t time.Time) Option {
+	return func(c *config) {
+		c.now = t
+	}
+}
+
+type config struct {
+	id  string
+	now time.Time
+}
+
+// Write writes items of the account as the camt.053 document.
+// Items can be in any order; entries are written oldest first.
+func Write(w io.Writer, account mono.Account, items []mono.StatementItem, opts ...Option) error {
+	c := config{now: time.Now()}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if len(account.IBAN) == 0 {
+		return errors.New("account IBAN must be set")
+	}
+
+	if len(c.id) == 0 {
+		c.id = truncate(account.ID+"-"+c.now.UTC().Format("20060102150405"), 35)
+	}
+
+	sorted := append([]mono.StatementItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time < sorted[j].Time
+	})
+
+	doc := document{
+		Xmlns: Namespace,
+		Statement: bkToCstmrStmt{
+			GroupHeader: groupHeader{MessageID: c.id, CreationTime: formatTime(c.now)},
+			Statement:   statement(c, account, sorted),
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func statement(c config, account mono.Account, items []mono.StatementItem) stmt {
+	currency := mono.CurrencyAlpha(account.CurrencyCodeISO4217)
+	from, to := c.now, c.now
+	opening, closing := account.Balance, account.Balance
+
+	if len(items) > 0 {
+		first, last := items[0], items[len(items)-1]
+		from, to = first.Time.Time(), last.Time.Time()
+		opening = openingBalance(items)
+		closing = opening
+
+		for _, item := range items {
+			if !item.Hold {
+				closing += item.Amount
+			}
+		}
+	}
+
+	s := stmt{
+		ID:           c.id,
+		CreationTime: formatTime(c.now),
+		Period:       &period{From: formatTime(from), To: formatTime(to)},
+		Account: acct{
+			ID:       acctID{IBAN: account.IBAN},
+			Currency: currency,
+			Servicer: &servicer{BIC: MonobankBIC},
+		},
+		Balances: []balance{
+			newBalance("OPBD", opening, account.CurrencyCodeISO4217, from),
+			newBalance("CLBD", closing, account.CurrencyCodeISO4217, to),
+		},
+	}
+
+	for _, item := range items {
+		s.Entries = append(s.Entries, newEntry(item, account.CurrencyCodeISO4217))
+	}
+
+	return s
+}
+
+// openingBalance returns the balance before the oldest item of any kind.
+// Starting from the first booked item instead would count holds made before it as booked.
+// Items of the same second are chained by their balances to find the oldest one.
+func openingBalance(items []mono.StatementItem) int64 {
+	oldest := items[0]
+
+	for _, item := range items {
+		if item.Time < oldest.Time {
+			oldest = item
+		}
+	}
+
+	after := map[int64]bool{}
+
+	for _, item := range items {
+		if item.Time == oldest.Time {
+			after[item.Balance] = true
+		}
+	}
+
+	for _, item := range items {
+		if item.Time == oldest.Time && !after[item.Balance-item.Amount] {
+			return item.Balance - item.Amount
+		}
+	}
+
+	return oldest.Balance - oldest.Amount
+}
+
+func newBalance(code string, value int64, currency int, at time.Time) balance {
+	return balance{
+		Type:      balanceType{Code: code},
+		Amount:    newAmount(value, currency),
+		Indicator: indicator(value),
+		Date:      date{Date: at.UTC().Format("2006-01-02")},
+	}
+}
+
+func newEntry(item mono.StatementItem, currency int) entry {
+	status := "BOOK"
+	if item.Hold {
+		status = "PDNG"
+	}
+
+	e := entry{
+		Reference:     truncate(item.ID, 35),
+		Amount:        newAmount(item.Amount, currency),
+		Indicator:     indicator(item.Amount),
+		Status:        status,
+		BookingDate:   &dateTime{DateTime: formatTime(item.Time.Time())},
+		ValueDate:     &date{Date: item.Time.Time().UTC().Format("2006-01-02")},
+		ServicerRef:   truncate(item.ID, 35),
+		BankTxCode:    bankTxCode{Code: fmt.Sprintf("MCC%04d", item.MCC), Issuer: "ISO 18245"},
+		AdditionalInf: truncate(item.Description, 500),
+	}
+
+	tx := txDetails{
+		Refs:          refs{ServicerRef: truncate(item.ID, 35)},
+		AdditionalInf: truncate(item.Description, 500),
+	}
+
+	if item.CurrencyCodeISO4217 != currency && item.OperationAmount != 0 {
+		instructed := newAmount(item.OperationAmount, item.CurrencyCodeISO4217)
+		tx.AmountDetails = &amountDetails{Instructed: amountHolder{Amount: instructed}}
+	}
+
+	if item.CommissionRate != 0 {
+		tx.Charges = &charges{Amount: newAmount(item.CommissionRate, currency)}
+	}
+
+	tx.Parties = parties(item)
+
+	if len(item.Description) > 0 || len(item.Comment) > 0 {
+		tx.Remittance = &remittance{Unstructured: truncate(item.Description, maxText)}
+
+		if len(item.Comment) > 0 {
+			tx.Remittance.Structured = &structured{Additional: truncate(item.Comment, maxText)}
+		}
+	}
+
+	e.Details = &entryDetails{Tx: tx}
+
+	return e
+}
+
+// parties makes the counterparty the creditor for debits and the debtor for credits.
+func parties(item mono.StatementItem) *relatedParties {
+	if len(item.CounterName) == 0 && len(item.CounterIBAN) == 0 && len(item.CounterEdrpou) == 0 {
+		return nil
+	}
+
+	p := &party{Name: truncate(item.CounterName, maxText)}
+	if len(item.CounterEdrpou) > 0 {
+		p.ID = &partyID{Org: orgID{Other: other{ID: item.CounterEdrpou, Scheme: scheme{Code: "TXID"}}}}
+	}
+
+	var a *acct
+	if len(item.CounterIBAN) > 0 {
+		a = &acct{ID: acctID{IBAN: item.CounterIBAN}}
+	}
+
+	if item.Amount < 0 {
+		return &relatedParties{Creditor: p, CreditorAccount: a}
+	}
+
+	return &relatedParties{Debtor: p, DebtorAccount: a}
+}
+
+func newAmount(value int64, currency int) amount {
+	return amount{
+		Currency: mono.CurrencyAlpha(currency),
+		Value:    strings.TrimPrefix(mono.FormatAmount(value, currency), "-"),
+	}
+}
+
+func indicator(value int64) string {
+	if value < 0 {
+		return "DBIT"
+	}
+
+	return "CRDT"
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,339 @@
+package camt053_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/export/camt053"
+)
+
+var account = mono.Account{ID: "acc", IBAN: "UA733220010000026201234567890", CurrencyCodeISO4217: 980}
+
+// items are in the bank's order, the latest first.
+var items = []mono.StatementItem{{
+	ID:          "hold",
+	Time:        mono.Time(1554566347),
+	Description: "АТБ",
+	MCC:         5411,
+	Amount:      -1000,
+	Balance:     9948500,
+	Hold:        true,
+}, {
+	ID:             "transfer",
+	Time:           mono.Time(1554470000),
+	Description:    "Переказ на рахунок",
+	CounterName:    "ТОВ \"Ромашка\" & Ко",
+	CounterIBAN:    "UA213223130000026007233566001",
+	CounterEdrpou:  "12345678",
+	Comment:        "Оплата за рахунком №42",
+	MCC:            4829,
+	Amount:         -100500,
+	CommissionRate: 500,
+	Balance:        9949500,
+}, {
+	ID:                  "foreign",
+	Time:                mono.Time(1554466347),
+	Description:         "Amazon",
+	MCC:                 5999,
+	Amount:              -95000,
+	OperationAmount:     -3500,
+	CurrencyCodeISO4217: 840,
+	Balance:             10050000,
+}}
+
+// schema lists children of elements in the order of camt.053.001.02 sequences.
+// Elements the writer does not use are omitted.
+var schema = map[string][]string{
+	"Document":      {"BkToCstmrStmt"},
+	"BkToCstmrStmt": {"GrpHdr", "Stmt"},
+	"GrpHdr":        {"MsgId", "CreDtTm"},
+	"Stmt":          {"Id", "CreDtTm", "FrToDt", "Acct", "Bal", "Ntry"},
+	"FrToDt":        {"FrDtTm", "ToDtTm"},
+	"Acct":          {"Id", "Ccy", "Svcr"},
+	"DbtrAcct":      {"Id"},
+	"CdtrAcct":      {"Id"},
+	"Svcr":          {"FinInstnId"},
+	"FinInstnId":    {"BIC"},
+	"Bal":           {"Tp", "Amt", "CdtDbtInd", "Dt"},
+	"Tp":            {"CdOrPrtry"},
+	"CdOrPrtry":     {"Cd"},
+	"Ntry": {
+		"NtryRef", "Amt", "CdtDbtInd", "Sts", "BookgDt", "ValDt", "AcctSvcrRef", "BkTxCd", "NtryDtls", "AddtlNtryInf",
+	},
+	"BookgDt":   {"DtTm"},
+	"ValDt":     {"Dt"},
+	"BkTxCd":    {"Prtry"},
+	"Prtry":     {"Cd", "Issr"},
+	"NtryDtls":  {"TxDtls"},
+	"TxDtls":    {"Refs", "AmtDtls", "Chrgs", "RltdPties", "RmtInf", "AddtlTxInf"},
+	"Refs":      {"AcctSvcrRef"},
+	"AmtDtls":   {"InstdAmt"},
+	"InstdAmt":  {"Amt"},
+	"Chrgs":     {"Amt"},
+	"RltdPties": {"Dbtr", "DbtrAcct", "Cdtr", "CdtrAcct"},
+	"Cdtr":      {"Nm", "Id"},
+	"Dbtr":      {"Nm", "Id"},
+	"OrgId":     {"Othr"},
+	"Othr":      {"Id", "SchmeNm"},
+	"SchmeNm":   {"Cd"},
+	"RmtInf":    {"Ustrd", "Strd"},
+	"Strd":      {"AddtlRmtInf"},
+}
+
+// required lists children that must be present.
+var required = map[string][]string{
+	"GrpHdr":   {"MsgId", "CreDtTm"},
+	"Stmt":     {"Id", "CreDtTm", "Acct", "Bal"},
+	"Acct":     {"Id"},
+	"Bal":      {"Tp", "Amt", "CdtDbtInd", "Dt"},
+	"Ntry":     {"Amt", "CdtDbtInd", "Sts", "BkTxCd"},
+	"InstdAmt": {"Amt"},
+	"Chrgs":    {"Amt"},
+	"Othr":     {"Id"},
+}
+
+// maxLengths lists text length limits of the schema types.
+var maxLengths = map[string]int{
+	"MsgId": 35, "NtryRef": 35, "AcctSvcrRef": 35, "Nm": 140, "Ustrd": 140, "AddtlRmtInf": 140,
+	"AddtlNtryInf": 500, "AddtlTxInf": 500,
+}
+
+type element struct {
+	name     string
+	text     string
+	children []*element
+}
+
+func parse(t *testing.T, r io.Reader) *element {
+	t.Helper()
+
+	dec := xml.NewDecoder(r)
+	stack := []*element{{}}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Output is not the well-formed XML: %v", err)
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if tok.Name.Space != camt053.Namespace {
+				t.Fatalf("Element %s is not in the camt.053 namespace", tok.Name.Local)
+			}
+
+			el := &element{name: tok.Name.Local}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, el)
+			stack = append(stack, el)
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			stack[len(stack)-1].text += strings.TrimSpace(string(tok))
+		}
+	}
+
+	return stack[0].children[0]
+}
+
+func validate(t *testing.T, el *element) {
+	t.Helper()
+
+	if order, ok := schema[el.name]; ok {
+		pos := 0
+
+		for _, child := range el.children {
+			for pos < len(order) && order[pos] != child.name {
+				pos++
+			}
+
+			if pos == len(order) {
+				t.Fatalf("Element %s is not allowed in %s at this position", child.name, el.name)
+			}
+		}
+	}
+
+	for _, name := range required[el.name] {
+		if find(el, name) == nil {
+			t.Fatalf("Element %s is missing required %s", el.name, name)
+		}
+	}
+
+	if max, ok := maxLengths[el.name]; ok && len([]rune(el.text)) > max {
+		t.Fatalf("Element %s is longer than %d", el.name, max)
+	}
+
+	for _, child := range el.children {
+		validate(t, child)
+	}
+}
+
+func find(el *element, path ...string) *element {
+	for _, name := range path {
+		var found *element
+
+		for _, child := range el.children {
+			if child.name == name {
+				found = child
+				break
+			}
+		}
+
+		if found == nil {
+			return nil
+		}
+
+		el = found
+	}
+
+	return el
+}
+
+func TestWrite(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Unix(1554600000, 0)
+
+	if err := camt053.Write(buf, account, items, camt053.WithCreationTime(now)); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := parse(t, bytes.NewReader(buf.Bytes()))
+	validate(t, doc)
+
+	stmt := find(doc, "BkToCstmrStmt", "Stmt")
+	if find(stmt, "Id").text != "acc-20190407012000" || find(stmt, "Acct", "Id", "IBAN").text != account.IBAN {
+		t.Fatalf("Unexpected statement header:\n%s", buf)
+	}
+
+	var balances, entries []*element
+
+	for _, child := range stmt.children {
+		switch child.name {
+		case "Bal":
+			balances = append(balances, child)
+		case "Ntry":
+			entries = append(entries, child)
+		}
+	}
+
+	expectBalance(t, balances[0], "OPBD", "101450.00", "CRDT", "2019-04-05")
+	expectBalance(t, balances[1], "CLBD", "99495.00", "CRDT", "2019-04-06")
+
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	foreign, transfer, hold := entries[0], entries[1], entries[2]
+
+	expectText(t, foreign, "950.00", "Amt")
+	expectText(t, foreign, "DBIT", "CdtDbtInd")
+	expectText(t, foreign, "BOOK", "Sts")
+	expectText(t, foreign, "MCC5999", "BkTxCd", "Prtry", "Cd")
+	expectText(t, foreign, "35.00", "NtryDtls", "TxDtls", "AmtDtls", "InstdAmt", "Amt")
+
+	tx := find(transfer, "NtryDtls", "TxDtls")
+	expectText(t, tx, "5.00", "Chrgs", "Amt")
+	expectText(t, tx, "ТОВ \"Ромашка\" & Ко", "RltdPties", "Cdtr", "Nm")
+	expectText(t, tx, "12345678", "RltdPties", "Cdtr", "Id", "OrgId", "Othr", "Id")
+	expectText(t, tx, "UA213223130000026007233566001", "RltdPties", "CdtrAcct", "Id", "IBAN")
+	expectText(t, tx, "Оплата за рахунком №42", "RmtInf", "Strd", "AddtlRmtInf")
+
+	expectText(t, hold, "PDNG", "Sts")
+}
+
+func TestWrite_ForeignCommission(t *testing.T) {
+	// The commission is in the account currency, so it keeps the hryvnia kopecks.
+	yen := mono.StatementItem{
+		ID:                  "yen",
+		Time:                mono.Time(1554466347),
+		Description:         "Tokyo Store",
+		MCC:                 5999,
+		Amount:              -31234,
+		OperationAmount:     -1100,
+		CurrencyCodeISO4217: 392,
+		CommissionRate:      1234,
+		Balance:             100000,
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := camt053.Write(buf, account, []mono.StatementItem{yen}); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := parse(t, bytes.NewReader(buf.Bytes()))
+	validate(t, doc)
+
+	tx := find(doc, "BkToCstmrStmt", "Stmt", "Ntry", "NtryDtls", "TxDtls")
+	expectText(t, tx, "1100", "AmtDtls", "InstdAmt", "Amt")
+	expectText(t, tx, "12.34", "Chrgs", "Amt")
+
+	if !strings.Contains(buf.String(), `<Amt Ccy="UAH">12.34</Amt>`) {
+		t.Fatalf("Charges must be in the account currency:\n%s", buf)
+	}
+}
+
+func TestWrite_HoldBeforeBooked(t *testing.T) {
+	// The bank lists items of the same second latest first, so the hold is the oldest one.
+	items := []mono.StatementItem{
+		{ID: "taxi", Time: mono.Time(1554466347), Amount: -100, Balance: 9000},
+		{ID: "hold", Time: mono.Time(1554466347), Amount: -900, Balance: 9100, Hold: true},
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := camt053.Write(buf, account, items); err != nil {
+		t.Fatal(err)
+	}
+
+	var balances []*element
+
+	for _, child := range find(parse(t, bytes.NewReader(buf.Bytes())), "BkToCstmrStmt", "Stmt").children {
+		if child.name == "Bal" {
+			balances = append(balances, child)
+		}
+	}
+
+	expectBalance(t, balances[0], "OPBD", "100.00", "CRDT", "2019-04-05")
+	expectBalance(t, balances[1], "CLBD", "99.00", "CRDT", "2019-04-05")
+}
+
+func TestWrite_Fail(t *testing.T) {
+	err := camt053.Write(&bytes.Buffer{}, mono.Account{ID: "acc"}, items)
+	if err == nil || err.Error() != "account IBAN must be set" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func expectBalance(t *testing.T, bal *element, code, amount, indicator, date string) {
+	t.Helper()
+
+	expectText(t, bal, code, "Tp", "CdOrPrtry", "Cd")
+	expectText(t, bal, amount, "Amt")
+	expectText(t, bal, indicator, "CdtDbtInd")
+	expectText(t, bal, date, "Dt", "Dt")
+}
+
+func expectText(t *testing.T, el *element, expected string, path ...string) {
+	t.Helper()
+
+	found := find(el, path...)
+	if found == nil {
+		t.Fatalf("Element %s has no %s", el.name, strings.Join(path, "/"))
+	}
+
+	if found.text != expected {
+		t.Fatalf("Element %s/%s is %q, expected %q", el.name, strings.Join(path, "/"), found.text, expected)
+	}
+}
@@ -0,0 +1,159 @@
+package camt053
+
+import "encoding/xml"
+
+// Types below mirror the subset of the camt.053.001.02 schema the writer uses.
+// Field order follows the sequence order of the schema.
+
+type document struct {
+	XMLName   xml.Name      `xml:"Document"`
+	Xmlns     string        `xml:"xmlns,attr"`
+	Statement bkToCstmrStmt `xml:"BkToCstmrStmt"`
+}
+
+type bkToCstmrStmt struct {
+	GroupHeader groupHeader `xml:"GrpHdr"`
+	Statement   stmt        `xml:"Stmt"`
+}
+
+type groupHeader struct {
+	MessageID    string `xml:"MsgId"`
+	CreationTime string `xml:"CreDtTm"`
+}
+
+type stmt struct {
+	ID           string    `xml:"Id"`
+	CreationTime string    `xml:"CreDtTm"`
+	Period       *period   `xml:"FrToDt"`
+	Account      acct      `xml:"Acct"`
+	Balances     []balance `xml:"Bal"`
+	Entries      []entry   `xml:"Ntry"`
+}
+
+type period struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+type acct struct {
+	ID       acctID    `xml:"Id"`
+	Currency string    `xml:"Ccy,omitempty"`
+	Servicer *servicer `xml:"Svcr"`
+}
+
+type acctID struct {
+	IBAN string `xml:"IBAN"`
+}
+
+type servicer struct {
+	BIC string `xml:"FinInstnId>BIC"`
+}
+
+type balance struct {
+	Type      balanceType `xml:"Tp"`
+	Amount    amount      `xml:"Amt"`
+	Indicator string      `xml:"CdtDbtInd"`
+	Date      date        `xml:"Dt"`
+}
+
+type balanceType struct {
+	Code string `xml:"CdOrPrtry>Cd"`
+}
+
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type date struct {
+	Date string `xml:"Dt"`
+}
+
+type dateTime struct {
+	DateTime string `xml:"DtTm"`
+}
+
+type entry struct {
+	Reference     string        `xml:"NtryRef,omitempty"`
+	Amount        amount        `xml:"Amt"`
+	Indicator     string        `xml:"CdtDbtInd"`
+	Status        string        `xml:"Sts"`
+	BookingDate   *dateTime     `xml:"BookgDt"`
+	ValueDate     *date         `xml:"ValDt"`
+	ServicerRef   string        `xml:"AcctSvcrRef,omitempty"`
+	BankTxCode    bankTxCode    `xml:"BkTxCd"`
+	Details       *entryDetails `xml:"NtryDtls"`
+	AdditionalInf string        `xml:"AddtlNtryInf,omitempty"`
+}
+
+type bankTxCode struct {
+	Code   string `xml:"Prtry>Cd"`
+	Issuer string `xml:"Prtry>Issr"`
+}
+
+type entryDetails struct {
+	Tx txDetails `xml:"TxDtls"`
+}
+
+type txDetails struct {
+	Refs          refs            `xml:"Refs"`
+	AmountDetails *amountDetails  `xml:"AmtDtls"`
+	Charges       *charges        `xml:"Chrgs"`
+	Parties       *relatedParties `xml:"RltdPties"`
+	Remittance    *remittance     `xml:"RmtInf"`
+	AdditionalInf string          `xml:"AddtlTxInf,omitempty"`
+}
+
+type refs struct {
+	ServicerRef string `xml:"AcctSvcrRef"`
+}
+
+type amountDetails struct {
+	Instructed amountHolder `xml:"InstdAmt"`
+}
+
+type amountHolder struct {
+	Amount amount `xml:"Amt"`
+}
+
+type charges struct {
+	Amount amount `xml:"Amt"`
+}
+
+type relatedParties struct {
+	Debtor          *party `xml:"Dbtr"`
+	DebtorAccount   *acct  `xml:"DbtrAcct"`
+	Creditor        *party `xml:"Cdtr"`
+	CreditorAccount *acct  `xml:"CdtrAcct"`
+}
+
+type party struct {
+	Name string   `xml:"Nm,omitempty"`
+	ID   *partyID `xml:"Id"`
+}
+
+type partyID struct {
+	Org orgID `xml:"OrgId"`
+}
+
+type orgID struct {
+	Other other `xml:"Othr"`
+}
+
+type other struct {
+	ID     string `xml:"Id"`
+	Scheme scheme `xml:"SchmeNm"`
+}
+
+type scheme struct {
+	Code string `xml:"Cd"`
+}
+
+type remittance struct {
+	Unstructured string      `xml:"Ustrd,omitempty"`
+	Structured   *structured `xml:"Strd"`
+}
+
+type structured struct {
+	Additional string `xml:"AddtlRmtInf"`
+}
@@ -0,0 +1,273 @@
+// Package csv writes statements as CSV with the selectable columns.
+//
+// Amounts are written in major units of their currency, like "-950.00",
+// so the file can be opened in spreadsheets without additional conversion:
+//
+//	w := csv.NewWriter(file, account, csv.WithBOM(), csv.WithSeparator(';'), csv.WithDecimalSeparator(','))
+//	if err := w.WriteAll(items); err != nil {
+//	  return err
+//	}
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Column is the column the writer can output.
+type Column string
+
+const (
+	// ColumnTime is the time of the transaction.
+	ColumnTime Column = "time"
+	// ColumnID is the transaction identifier.
+	ColumnID Column = "id"
+	// ColumnDescription is the transaction description.
+	ColumnDescription Column = "description"
+	// ColumnMCC is the merchant category code.
+	ColumnMCC Column = "mcc"
+	// ColumnMCCDescription is the description of the merchant category code.
+	ColumnMCCDescription Column = "mcc_description"
+	// ColumnCategory is the spending category of the merchant category code.
+	ColumnCategory Column = "category"
+	// ColumnAmount is the amount in the account currency.
+	ColumnAmount Column = "amount"
+	// ColumnCurrency is the account currency.
+	ColumnCurrency Column = "currency"
+	// ColumnOperationAmount is the amount in the transaction currency.
+	ColumnOperationAmount Column = "operation_amount"
+	// ColumnOperationCurrency is the transaction currency.
+	ColumnOperationCurrency Column = "operation_currency"
+	// ColumnCommission is the commission in the account currency.
+	ColumnCommission Column = "commission"
+	// ColumnCashback is the cashback in the account currency.
+	ColumnCashback Column = "cashback"
+	// ColumnBalance is the balance after the transaction in the account currency.
+	ColumnBalance Column = "balance"
+	// ColumnHold tells whether the transaction is on hold.
+	ColumnHold Column = "hold"
+	// ColumnAccount is the account identifier.
+	ColumnAccount Column = "account"
+	// ColumnIBAN is the account's IBAN.
+	ColumnIBAN Column = "iban"
+)
+
+// DefaultColumns returns columns the writer outputs unless others are set.
+func DefaultColumns() []Column {
+	return []Column{
+		ColumnTime, ColumnDescription, ColumnMCCDescription, ColumnAmount, ColumnCurrency,
+		ColumnOperationAmount, ColumnOperationCurrency, ColumnCommission, ColumnCashback, ColumnBalance,
+	}
+}
+
+// Option allows to change default values for the writer.
+type Option func(*Writer)
+
+// WithColumns sets columns and their order.
+func WithColumns(columns ...Column) Option {
+	return func(w *Writer) {
+		w.columns = columns
+	}
+}
+
+// WithLocation sets the time zone of timestamps. Default is UTC.
+func WithLocation(loc *time.Location) Option {
+	return func(w *Writer) {
+		w.loc = loc
+	}
+}
+
+// WithTimeFormat sets the layout of timestamps. Default is ISO 8601 `time.RFC3339`.
+func WithTimeFormat(layout string) Option {
+	return func(w *Writer) {
+		w.timeFormat = layout
+	}
+}
+
+// WithSeparator sets the field separator. Default is comma.
+func WithSeparator(separator rune) Option {
+	return func(w *Writer) {
+		w.separator = separator
+	}
+}
+
+// WithDecimalSeparator sets the separator of amounts' fractional part. Default is dot.
+// Spreadsheets in locales with comma as the decimal separator need it along with `WithSeparator(';')`.
+func WithDecimalSeparator(separator rune) Option {
+	return func(w *Writer) {
+		w.decimal = string(separator)
+	}
+}
+
+// WithBOM makes the writer start the output with UTF-8 byte order mark.
+// Excel needs it to detect UTF-8 encoding.
+func WithBOM() Option {
+	return func(w *Writer) {
+		w.bom = true
+	}
+}
+
+// WithoutHeader makes the writer skip the header row.
+func WithoutHeader() Option {
+	return func(w *Writer) {
+		w.header = false
+	}
+}
+
+// Writer writes statement items of the single account as CSV records.
+// The header, if enabled, is written before the first item.
+type Writer struct {
+	w       io.Writer
+	csv     *stdcsv.Writer
+	account mono.Account
+
+	columns    []Column
+	loc        *time.Location
+	timeFormat string
+	separator  rune
+	decimal    string
+	bom        bool
+	header     bool
+
+	started bool
+}
+
+// NewWriter creates the writer for items of the account.
+func NewWriter(w io.Writer, account mono.Account, opts ...Option) *Writer {
+	cw := &Writer{
+		w:          w,
+		account:    account,
+		columns:    DefaultColumns(),
+		loc:        time.UTC,
+		timeFormat: time.RFC3339,
+		separator:  ',',
+		decimal:    ".",
+		header:     true,
+	}
+
+	for _, opt := range opts {
+		opt(cw)
+	}
+
+	cw.csv = stdcsv.NewWriter(w)
+	cw.csv.Comma = cw.separator
+
+	return cw
+}
+
+// Write writes the single item.
+// Call Flush to make sure the record reaches the underlying writer.
+func (w *Writer) Write(item mono.StatementItem) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(w.columns))
+
+	for i, column := range w.columns {
+		value, err := w.value(column, item)
+		if err != nil {
+			return err
+		}
+
+		record[i] = value
+	}
+
+	return w.csv.Write(record)
+}
+
+// WriteAll writes all items and flushes the writer.
+func (w *Writer) WriteAll(items []mono.StatementItem) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := w.Write(item); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// Flush writes buffered records to the underlying writer.
+func (w *Writer) Flush() error {
+	w.csv.Flush()
+
+	return w.csv.Error()
+}
+
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+
+	w.started = true
+
+	if w.bom {
+		if _, err := io.WriteString(w.w, "\uFEFF"); err != nil {
+			return err
+		}
+	}
+
+	if !w.header {
+		return nil
+	}
+
+	header := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		header[i] = string(column)
+	}
+
+	return w.csv.Write(header)
+}
+
+func (w *Writer) value(column Column, item mono.StatementItem) (string, error) {
+	switch column {
+	case ColumnTime:
+		return item.Time.Time().In(w.loc).Format(w.timeFormat), nil
+	case ColumnID:
+		return item.ID, nil
+	case ColumnDescription:
+		return item.Description, nil
+	case ColumnMCC:
+		return strconv.Itoa(item.MCC), nil
+	case ColumnMCCDescription:
+		return mono.MCCDescription(item.MCC), nil
+	case ColumnCategory:
+		return string(mono.ClassifyMCC(item.MCC)), nil
+	case ColumnAmount:
+		return w.amount(item.Amount, w.account.CurrencyCodeISO4217), nil
+	case ColumnCurrency:
+		return mono.CurrencyAlpha(w.account.CurrencyCodeISO4217), nil
+	case ColumnOperationAmount:
+		return w.amount(item.OperationAmount, item.CurrencyCodeISO4217), nil
+	case ColumnOperationCurrency:
+		return mono.CurrencyAlpha(item.CurrencyCodeISO4217), nil
+	case ColumnCommission:
+		return w.amount(item.CommissionRate, w.account.CurrencyCodeISO4217), nil
+	case ColumnCashback:
+		return w.amount(item.CashbackAmount, w.account.CurrencyCodeISO4217), nil
+	case ColumnBalance:
+		return w.amount(item.Balance, w.account.CurrencyCodeISO4217), nil
+	case ColumnHold:
+		return strconv.FormatBool(item.Hold), nil
+	case ColumnAccount:
+		return w.account.ID, nil
+	case ColumnIBAN:
+		return w.account.IBAN, nil
+	}
+
+	return "", errors.New("unknown column " + string(column))
+}
+
+func (w *Writer) amount(amount int64, currency int) string {
+	return strings.Replace(mono.FormatAmount(amount, currency), ".", w.decimal, 1)
+}
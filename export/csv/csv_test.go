@@ -0,0 +1,130 @@
+package csv_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/export/csv"
+)
+
+var account = mono.Account{ID: "acc", IBAN: "UA733220010000026201234567890", CurrencyCodeISO4217: 980}
+
+var items = []mono.StatementItem{{
+	ID:                  "ZuHWzqkKGVo=",
+	Time:                mono.Time(1554466347),
+	Description:         "Покупка \"щастя\", друга",
+	MCC:                 7997,
+	Amount:              -95000,
+	OperationAmount:     -3500,
+	CurrencyCodeISO4217: 840,
+	CommissionRate:      0,
+	CashbackAmount:      950,
+	Balance:             10050000,
+}, {
+	ID:                  "second",
+	Time:                mono.Time(1554470000),
+	Description:         "Сільпо",
+	MCC:                 5411,
+	Hold:                true,
+	Amount:              -5,
+	OperationAmount:     -5,
+	CurrencyCodeISO4217: 980,
+	Balance:             10049995,
+}}
+
+func TestWriter_Defaults(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := csv.NewWriter(buf, account).WriteAll(items); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "time,description,mcc_description,amount,currency," +
+		"operation_amount,operation_currency,commission,cashback,balance\n" +
+		"2019-04-05T12:12:27Z,\"Покупка \"\"щастя\"\", друга\",\"Clubs, Country Clubs, Memberships\"," +
+		"-950.00,UAH,-35.00,USD,0.00,9.50,100500.00\n" +
+		"2019-04-05T13:13:20Z,Сільпо,\"Grocery Stores, Supermarkets\",-0.05,UAH,-0.05,UAH,0.00,0.00,100499.95\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+}
+
+func TestWriter_Excel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	kyiv := time.FixedZone("EEST", 3*60*60)
+
+	w := csv.NewWriter(buf, account,
+		csv.WithColumns(csv.ColumnIBAN, csv.ColumnTime, csv.ColumnID, csv.ColumnAmount, csv.ColumnHold, csv.ColumnCategory),
+		csv.WithLocation(kyiv),
+		csv.WithTimeFormat("02.01.2006 15:04:05"),
+		csv.WithSeparator(';'),
+		csv.WithDecimalSeparator(','),
+		csv.WithBOM(),
+		csv.WithoutHeader(),
+	)
+
+	for _, item := range items {
+		if err := w.Write(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\uFEFF" +
+		"UA733220010000026201234567890;05.04.2019 15:12:27;ZuHWzqkKGVo=;-950,00;false;Entertainment\n" +
+		"UA733220010000026201234567890;05.04.2019 16:13:20;second;-0,05;true;Groceries\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+}
+
+func TestWriter_ForeignCommission(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf, account, csv.WithColumns(
+		csv.ColumnAmount, csv.ColumnOperationAmount, csv.ColumnOperationCurrency, csv.ColumnCommission,
+	))
+
+	foreign := mono.StatementItem{
+		ID:                  "yen",
+		Amount:              -31234,
+		OperationAmount:     -1100,
+		CurrencyCodeISO4217: 392,
+		CommissionRate:      1234,
+	}
+
+	if err := w.WriteAll([]mono.StatementItem{foreign}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "amount,operation_amount,operation_currency,commission\n-312.34,-1100,JPY,12.34\n"
+	if buf.String() != expected {
+		t.Fatalf("Commission must be in the account currency:\n%s", buf)
+	}
+}
+
+func TestWriter_Empty(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := csv.NewWriter(buf, account, csv.WithColumns(csv.ColumnAccount, csv.ColumnMCC)).WriteAll(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "account,mcc\n" {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+}
+
+func TestWriter_UnknownColumn(t *testing.T) {
+	err := csv.NewWriter(&bytes.Buffer{}, account, csv.WithColumns("nope")).Write(items[0])
+	if err == nil || !strings.Contains(err.Error(), "unknown column nope") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package ledger
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// IDs is the set of exported item IDs that can be stored in the file, one ID per line.
+type IDs map[string]struct{}
+
+// LoadIDs reads the set from the file. The missing file is the empty set.
+func LoadIDs(path string) (IDs, error) {
+	ids := IDs{}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return ids, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ids: %v", err)
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); len(id) > 0 {
+			ids.Add(id)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ids: %v", err)
+	}
+
+	return ids, nil
+}
+
+// Has tells whether the ID is in the set.
+func (ids IDs) Has(id string) bool {
+	_, ok := ids[id]
+	return ok
+}
+
+// Add adds the ID to the set.
+func (ids IDs) Add(id string) {
+	ids[id] = struct{}{}
+}
+
+// Save writes the set to the file, sorted.
+func (ids IDs) Save(path string) error {
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+
+	sort.Strings(sorted)
+
+	content := strings.Join(sorted, "\n")
+	if len(sorted) > 0 {
+		content += "\n"
+	}
+
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write ids: %v", err)
+	}
+
+	return nil
+}
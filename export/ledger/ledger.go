@@ -0,0 +1,508 @@
+// Package ledger writes statements as plain-text accounting journals
+// for ledger, hledger and beancount.
+//
+// Each settled item becomes the transaction between the Monobank account and the category account:
+//
+//	ids, _ := ledger.LoadIDs("exported.ids")
+//	e := ledger.New(ledger.Beancount,
+//	  ledger.WithAccounts(map[string]string{account.ID: "Assets:Mono:Black"}),
+//	  ledger.WithRates(currencies),
+//	  ledger.WithSeen(ids),
+//	)
+//	if _, err := e.Export(file, account, items); err != nil {
+//	  return err
+//	}
+//	err := ids.Save("exported.ids")
+//
+// Holds are skipped until they settle, as their amounts may still change.
+// The bank includes holds in balances, so balance assertions subtract holds made up to the item.
+// The balance before the first exported item is written once per account as the opening balance.
+// Beancount accounts are opened on their first use, and currencies without the alphabetic code are rejected,
+// as beancount does not accept numeric commodities.
+package ledger
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Format is the journal syntax.
+type Format int
+
+const (
+	// Ledger is the syntax of ledger-cli.
+	Ledger Format = iota
+	// Hledger is the syntax of hledger.
+	Hledger
+	// Beancount is the syntax of beancount.
+	Beancount
+)
+
+// Seen remembers which items were already exported.
+type Seen interface {
+	Has(id string) bool
+	Add(id string)
+}
+
+// Option allows to change default values for the exporter.
+type Option func(*Exporter)
+
+// WithAccounts maps Monobank account IDs to journal accounts.
+// Unmapped accounts become `Assets:Mono:<currency>`.
+func WithAccounts(accounts map[string]string) Option {
+	return func(e *Exporter) {
+		e.accounts = accounts
+	}
+}
+
+// WithCategories maps MCC categories to journal accounts.
+// Unmapped categories become `Expenses:<category>` for spending and `Income:<category>` for incomes.
+func WithCategories(categories map[mono.MCCCategory]string) Option {
+	return func(e *Exporter) {
+		e.categories = categories
+	}
+}
+
+// WithCommissionAccount sets the account for commissions. Default is `Expenses:Fees:Mono`.
+func WithCommissionAccount(account string) Option {
+	return func(e *Exporter) {
+		e.commission = account
+	}
+}
+
+// WithCashbackAccounts sets the asset account where cashback accrues and the income account it comes from.
+// Defaults are `Assets:Mono:Cashback` and `Income:Cashback`.
+func WithCashbackAccounts(asset, income string) Option {
+	return func(e *Exporter) {
+		e.cashbackAsset = asset
+		e.cashbackIncome = income
+	}
+}
+
+// WithOpeningAccount sets the account the opening balance comes from. Default is `Equity:Opening-Balances`.
+func WithOpeningAccount(account string) Option {
+	return func(e *Exporter) {
+		e.opening = account
+	}
+}
+
+// WithRates sets currency rates to write price directives for foreign operations.
+func WithRates(rates []mono.CurrencyInfo) Option {
+	return func(e *Exporter) {
+		e.rates = rates
+	}
+}
+
+// WithSeen makes the exporter skip items already in the set and add exported ones to it.
+func WithSeen(seen Seen) Option {
+	return func(e *Exporter) {
+		e.seen = seen
+	}
+}
+
+// WithLocation sets the time zone of dates. Default is UTC.
+func WithLocation(loc *time.Location) Option {
+	return func(e *Exporter) {
+		e.loc = loc
+	}
+}
+
+// Exporter converts statement items to journal entries.
+type Exporter struct {
+	format         Format
+	accounts       map[string]string
+	categories     map[mono.MCCCategory]string
+	commission     string
+	cashbackAsset  string
+	cashbackIncome string
+	opening        string
+	rates          []mono.CurrencyInfo
+	seen           Seen
+	loc            *time.Location
+}
+
+// New creates the exporter for the journal format.
+func New(format Format, opts ...Option) *Exporter {
+	e := &Exporter{
+		format:         format,
+		commission:     "Expenses:Fees:Mono",
+		cashbackAsset:  "Assets:Mono:Cashback",
+		cashbackIncome: "Income:Cashback",
+		opening:        "Equity:Opening-Balances",
+		loc:            time.UTC,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+type posting struct {
+	account string
+	amount  string
+	// price is the total cost in the account currency for foreign operations.
+	price string
+	// balance is the account balance after the posting, for balance assertions.
+	balance string
+}
+
+const (
+	// openingPrefix marks in the seen set accounts whose opening balance was written.
+	openingPrefix = "opening:"
+	// openPrefix marks in the seen set beancount accounts that were opened.
+	openPrefix = "open:"
+)
+
+// Export writes items of the account not seen before, oldest first, and returns how many were written.
+// Items are marked as seen only after they were written.
+func (e *Exporter) Export(w io.Writer, account mono.Account, items []mono.StatementItem) (int, error) {
+	if e.format != Ledger && e.format != Hledger && e.format != Beancount {
+		return 0, errors.New("unknown format " + strconv.Itoa(int(e.format)))
+	}
+
+	sorted := make([]mono.StatementItem, 0, len(items))
+
+	var holds []mono.StatementItem
+
+	for _, item := range items {
+		if item.Hold {
+			holds = append(holds, item)
+		} else if e.seen == nil || !e.seen.Has(item.ID) {
+			sorted = append(sorted, item)
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time < sorted[j].Time
+	})
+
+	if err := e.checkCommodities(account, sorted); err != nil {
+		return 0, err
+	}
+
+	opened := map[string]bool{}
+
+	if len(sorted) > 0 && (e.seen == nil || !e.seen.Has(openingPrefix+account.ID)) {
+		var sb strings.Builder
+
+		first := sorted[0]
+		before := first.Time.Time().In(e.loc).AddDate(0, 0, -1).Format("2006-01-02")
+		keys := e.writeOpen(&sb, before, opened, e.account(account), e.opening)
+
+		sb.WriteString(e.openingEntry(account, first, settledBalance(first, holds)-first.Amount))
+
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return 0, err
+		}
+
+		e.markSeen(append(keys, openingPrefix+account.ID)...)
+	}
+
+	prices := map[string]bool{}
+
+	for i, item := range sorted {
+		var sb strings.Builder
+
+		balance := settledBalance(item, holds)
+		postings := e.postings(account, item, balance)
+
+		accounts := make([]string, 0, len(postings))
+		for _, p := range postings {
+			accounts = append(accounts, p.account)
+		}
+
+		keys := e.writeOpen(&sb, e.date(item.Time), opened, accounts...)
+		e.writePrice(&sb, account, item, prices)
+		e.writeTransaction(&sb, item, postings)
+
+		lastOfDay := i+1 == len(sorted) || e.date(sorted[i+1].Time) != e.date(item.Time)
+		if e.format == Beancount && lastOfDay {
+			e.writeBalance(&sb, account, item, balance)
+		}
+
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return i, err
+		}
+
+		e.markSeen(append(keys, item.ID)...)
+	}
+
+	return len(sorted), nil
+}
+
+// checkCommodities rejects currencies beancount cannot name, as it takes only alphabetic commodities.
+func (e *Exporter) checkCommodities(account mono.Account, items []mono.StatementItem) error {
+	if e.format != Beancount {
+		return nil
+	}
+
+	codes := []int{account.CurrencyCodeISO4217}
+
+	for _, item := range items {
+		if item.CurrencyCodeISO4217 != account.CurrencyCodeISO4217 && item.OperationAmount != 0 {
+			codes = append(codes, item.CurrencyCodeISO4217)
+		}
+	}
+
+	for _, code := range codes {
+		if _, ok := mono.CurrencyCode(mono.CurrencyAlpha(code)); !ok {
+			return errors.New("currency " + strconv.Itoa(code) + " has no alphabetic code for beancount")
+		}
+	}
+
+	return nil
+}
+
+// writeOpen opens beancount accounts on the date, once per account, and returns their keys for the seen set.
+func (e *Exporter) writeOpen(sb *strings.Builder, date string, opened map[string]bool, accounts ...string) []string {
+	if e.format != Beancount {
+		return nil
+	}
+
+	var keys []string
+
+	for _, account := range accounts {
+		key := openPrefix + account
+		if opened[key] || (e.seen != nil && e.seen.Has(key)) {
+			continue
+		}
+
+		opened[key] = true
+		keys = append(keys, key)
+
+		sb.WriteString(date + " open " + account + "\n\n")
+	}
+
+	return keys
+}
+
+func (e *Exporter) markSeen(keys ...string) {
+	if e.seen == nil {
+		return
+	}
+
+	for _, key := range keys {
+		e.seen.Add(key)
+	}
+}
+
+// settledBalance is the balance after the item without holds, which the bank includes in balances.
+// The hold made in the same second as the item is taken as made before it.
+func settledBalance(item mono.StatementItem, holds []mono.StatementItem) int64 {
+	balance := item.Balance
+
+	for _, hold := range holds {
+		if hold.Time <= item.Time {
+			balance -= hold.Amount
+		}
+	}
+
+	return balance
+}
+
+// openingEntry sets the account balance before the first item. Ledger and hledger assign the balance,
+// and beancount pads the account on the day before, so earlier entries of the account do not break it.
+func (e *Exporter) openingEntry(account mono.Account, first mono.StatementItem, balance int64) string {
+	amount := mono.FormatAmount(balance, account.CurrencyCodeISO4217) +
+		" " + mono.CurrencyAlpha(account.CurrencyCodeISO4217)
+
+	if e.format == Beancount {
+		day := first.Time.Time().In(e.loc)
+		before := day.AddDate(0, 0, -1).Format("2006-01-02")
+
+		return before + " pad " + e.account(account) + " " + e.opening + "\n\n" +
+			day.Format("2006-01-02") + " balance " + e.account(account) + "  " + amount + "\n\n"
+	}
+
+	return e.date(first.Time) + " * Opening balance\n" +
+		"    " + e.account(account) + "  = " + amount + "\n" +
+		"    " + e.opening + "\n\n"
+}
+
+// postings moves the item amount between the account and the category, along with commission and cashback.
+func (e *Exporter) postings(account mono.Account, item mono.StatementItem, balance int64) []posting {
+	currency := mono.CurrencyAlpha(account.CurrencyCodeISO4217)
+	amount := func(v int64) string {
+		return mono.FormatAmount(v, account.CurrencyCodeISO4217) + " " + currency
+	}
+
+	asset := posting{account: e.account(account), amount: amount(item.Amount)}
+	if e.format != Beancount {
+		asset.balance = amount(balance)
+	}
+
+	// The amount includes the commission, which is in the account currency like the amount.
+	category := posting{account: e.category(item), amount: amount(-item.Amount - item.CommissionRate)}
+
+	if item.CurrencyCodeISO4217 != account.CurrencyCodeISO4217 && item.OperationAmount != 0 {
+		category.amount = mono.FormatAmount(-item.OperationAmount, item.CurrencyCodeISO4217) +
+			" " + mono.CurrencyAlpha(item.CurrencyCodeISO4217)
+		category.price = amount(abs(item.Amount + item.CommissionRate))
+	}
+
+	postings := []posting{asset, category}
+
+	if item.CommissionRate != 0 {
+		postings = append(postings, posting{account: e.commission, amount: amount(item.CommissionRate)})
+	}
+
+	if item.CashbackAmount != 0 {
+		postings = append(postings,
+			posting{account: e.cashbackAsset, amount: amount(item.CashbackAmount)},
+			posting{account: e.cashbackIncome, amount: amount(-item.CashbackAmount)},
+		)
+	}
+
+	return postings
+}
+
+func (e *Exporter) writeTransaction(sb *strings.Builder, item mono.StatementItem, postings []posting) {
+	payee := item.Description
+	if len(item.CounterName) > 0 {
+		payee = item.CounterName
+	}
+
+	switch e.format {
+	case Beancount:
+		sb.WriteString(e.date(item.Time) + " * " + quote(payee) + " " + quote(item.Comment) + "\n")
+		sb.WriteString("  id: " + quote(item.ID) + "\n")
+	case Hledger:
+		sb.WriteString(e.date(item.Time) + " * " + e.description(payee, item.Comment) + "  ; id:" + item.ID + "\n")
+	default:
+		sb.WriteString(e.date(item.Time) + " * " + e.description(payee, item.Comment) + "\n")
+		sb.WriteString("    ; ID: " + item.ID + "\n")
+	}
+
+	for _, p := range postings {
+		line := "    " + p.account + "  " + p.amount
+		if len(p.price) > 0 {
+			line += " @@ " + p.price
+		}
+
+		if len(p.balance) > 0 {
+			line += " = " + p.balance
+		}
+
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\n")
+}
+
+// writePrice writes the price directive for the foreign operation, once per currency and day.
+func (e *Exporter) writePrice(
+	sb *strings.Builder, account mono.Account, item mono.StatementItem, written map[string]bool,
+) {
+	if item.CurrencyCodeISO4217 == account.CurrencyCodeISO4217 {
+		return
+	}
+
+	rate, ok := e.rate(item.CurrencyCodeISO4217, account.CurrencyCodeISO4217)
+	if !ok {
+		return
+	}
+
+	date := e.date(item.Time)
+	commodity := mono.CurrencyAlpha(item.CurrencyCodeISO4217)
+
+	if written[date+commodity] {
+		return
+	}
+
+	written[date+commodity] = true
+	base := mono.CurrencyAlpha(account.CurrencyCodeISO4217)
+	price := commodity + " " + strconv.FormatFloat(rate, 'f', -1, 64) + " " + base
+
+	if e.format == Beancount {
+		sb.WriteString(date + " price " + price + "\n\n")
+	} else {
+		sb.WriteString("P " + date + " " + price + "\n\n")
+	}
+}
+
+// writeBalance writes the beancount balance assertion, which checks the balance at the start of the day.
+func (e *Exporter) writeBalance(sb *strings.Builder, account mono.Account, item mono.StatementItem, settled int64) {
+	nextDay := item.Time.Time().In(e.loc).AddDate(0, 0, 1).Format("2006-01-02")
+	balance := mono.FormatAmount(settled, account.CurrencyCodeISO4217) +
+		" " + mono.CurrencyAlpha(account.CurrencyCodeISO4217)
+
+	sb.WriteString(nextDay + " balance " + e.account(account) + "  " + balance + "\n\n")
+}
+
+// rate finds the price of the currency, preferring the cross rate and falling back to the mid rate.
+func (e *Exporter) rate(currency, base int) (float64, bool) {
+	for _, r := range e.rates {
+		if r.CurrencyCodeAISO4217 != currency || r.CurrencyCodeBISO4217 != base {
+			continue
+		}
+
+		if r.RateCross > 0 {
+			return r.RateCross, true
+		}
+
+		if r.RateBuy > 0 && r.RateSell > 0 {
+			return (r.RateBuy + r.RateSell) / 2, true
+		}
+	}
+
+	return 0, false
+}
+
+func (e *Exporter) account(account mono.Account) string {
+	if name, ok := e.accounts[account.ID]; ok {
+		return name
+	}
+
+	return "Assets:Mono:" + mono.CurrencyAlpha(account.CurrencyCodeISO4217)
+}
+
+func (e *Exporter) category(item mono.StatementItem) string {
+	category := mono.ClassifyMCC(item.MCC)
+	if name, ok := e.categories[category]; ok {
+		return name
+	}
+
+	if item.Amount > 0 {
+		return "Income:" + string(category)
+	}
+
+	return "Expenses:" + string(category)
+}
+
+func (e *Exporter) date(t mono.Time) string {
+	if e.format == Ledger {
+		return t.Time().In(e.loc).Format("2006/01/02")
+	}
+
+	return t.Time().In(e.loc).Format("2006-01-02")
+}
+
+// description joins payee and note with the pipe, which ledger and hledger read as payee and note.
+func (e *Exporter) description(payee, note string) string {
+	description := strings.ReplaceAll(payee, "\n", " ")
+	if len(note) > 0 {
+		description += " | " + strings.ReplaceAll(note, "\n", " ")
+	}
+
+	return description
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,342 @@
+package ledger_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/export/ledger"
+)
+
+var account = mono.Account{ID: "acc", CurrencyCodeISO4217: 980}
+
+// items are in the bank's order, the latest first.
+var items = []mono.StatementItem{{
+	ID:          "hold",
+	Time:        mono.Time(1554566347),
+	Description: "АТБ",
+	MCC:         5411,
+	Amount:      -1000,
+	Balance:     9948500,
+	Hold:        true,
+}, {
+	ID:             "transfer",
+	Time:           mono.Time(1554470000),
+	Description:    "Переказ на картку",
+	CounterName:    "Ольга П.",
+	Comment:        "За вечерю",
+	MCC:            4829,
+	Amount:         -100500,
+	CommissionRate: 500,
+	Balance:        9949500,
+}, {
+	ID:                  "foreign",
+	Time:                mono.Time(1554466347),
+	Description:         "Amazon",
+	MCC:                 5999,
+	Amount:              -95000,
+	OperationAmount:     -3500,
+	CurrencyCodeISO4217: 840,
+	CashbackAmount:      950,
+	Balance:             10050000,
+}}
+
+var rates = []mono.CurrencyInfo{{CurrencyCodeAISO4217: 840, CurrencyCodeBISO4217: 980, RateBuy: 27, RateSell: 27.4}}
+
+func TestExport_Ledger(t *testing.T) {
+	expected := `2019/04/05 * Opening balance
+    Assets:Mono:Black  = 101450.00 UAH
+    Equity:Opening-Balances
+
+P 2019/04/05 USD 27.2 UAH
+
+2019/04/05 * Amazon
+    ; ID: foreign
+    Assets:Mono:Black  -950.00 UAH = 100500.00 UAH
+    Expenses:Shopping  35.00 USD @@ 950.00 UAH
+    Assets:Mono:Cashback  9.50 UAH
+    Income:Cashback  -9.50 UAH
+
+2019/04/05 * Ольга П. | За вечерю
+    ; ID: transfer
+    Assets:Mono:Black  -1005.00 UAH = 99495.00 UAH
+    Expenses:Transfers  1000.00 UAH
+    Expenses:Fees:Mono  5.00 UAH
+
+`
+
+	testExport(t, ledger.Ledger, expected)
+}
+
+func TestExport_Hledger(t *testing.T) {
+	expected := `2019-04-05 * Opening balance
+    Assets:Mono:Black  = 101450.00 UAH
+    Equity:Opening-Balances
+
+P 2019-04-05 USD 27.2 UAH
+
+2019-04-05 * Amazon  ; id:foreign
+    Assets:Mono:Black  -950.00 UAH = 100500.00 UAH
+    Expenses:Shopping  35.00 USD @@ 950.00 UAH
+    Assets:Mono:Cashback  9.50 UAH
+    Income:Cashback  -9.50 UAH
+
+2019-04-05 * Ольга П. | За вечерю  ; id:transfer
+    Assets:Mono:Black  -1005.00 UAH = 99495.00 UAH
+    Expenses:Transfers  1000.00 UAH
+    Expenses:Fees:Mono  5.00 UAH
+
+`
+
+	testExport(t, ledger.Hledger, expected)
+}
+
+func TestExport_Beancount(t *testing.T) {
+	expected := `2019-04-04 open Assets:Mono:Black
+
+2019-04-04 open Equity:Opening-Balances
+
+2019-04-04 pad Assets:Mono:Black Equity:Opening-Balances
+
+2019-04-05 balance Assets:Mono:Black  101450.00 UAH
+
+2019-04-05 open Expenses:Shopping
+
+2019-04-05 open Assets:Mono:Cashback
+
+2019-04-05 open Income:Cashback
+
+2019-04-05 price USD 27.2 UAH
+
+2019-04-05 * "Amazon" ""
+  id: "foreign"
+    Assets:Mono:Black  -950.00 UAH
+    Expenses:Shopping  35.00 USD @@ 950.00 UAH
+    Assets:Mono:Cashback  9.50 UAH
+    Income:Cashback  -9.50 UAH
+
+2019-04-05 open Expenses:Transfers
+
+2019-04-05 open Expenses:Fees:Mono
+
+2019-04-05 * "Ольга П." "За вечерю"
+  id: "transfer"
+    Assets:Mono:Black  -1005.00 UAH
+    Expenses:Transfers  1000.00 UAH
+    Expenses:Fees:Mono  5.00 UAH
+
+2019-04-06 balance Assets:Mono:Black  99495.00 UAH
+
+`
+
+	testExport(t, ledger.Beancount, expected)
+}
+
+func testExport(t *testing.T, format ledger.Format, expected string) {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	ids := ledger.IDs{}
+	e := ledger.New(format,
+		ledger.WithAccounts(map[string]string{"acc": "Assets:Mono:Black"}),
+		ledger.WithCategories(map[mono.MCCCategory]string{mono.CategoryTransfers: "Expenses:Transfers"}),
+		ledger.WithRates(rates),
+		ledger.WithSeen(ids),
+	)
+
+	n, err := e.Export(buf, account, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 2 || buf.String() != expected {
+		t.Fatalf("Unexpected output of %d items:\n%s", n, buf)
+	}
+
+	if !ids.Has("foreign") || !ids.Has("transfer") || !ids.Has("opening:acc") || ids.Has("hold") {
+		t.Fatalf("Unexpected seen IDs: %v", ids)
+	}
+
+	buf.Reset()
+
+	if n, err = e.Export(buf, account, items); err != nil || n != 0 || buf.Len() != 0 {
+		t.Fatalf("Expected seen items to be skipped, got %d items: %v", n, err)
+	}
+}
+
+func TestExport_HoldBeforeSettled(t *testing.T) {
+	// The bank includes the hold in the balance of the transfer made after it.
+	withHold := []mono.StatementItem{{
+		ID:          "transfer",
+		Time:        mono.Time(1554470000),
+		Description: "Переказ",
+		MCC:         4829,
+		Amount:      -5000,
+		Balance:     94000,
+	}, {
+		ID:          "hold",
+		Time:        mono.Time(1554466347),
+		Description: "АТБ",
+		MCC:         5411,
+		Amount:      -1000,
+		Balance:     99000,
+		Hold:        true,
+	}}
+
+	buf := &bytes.Buffer{}
+
+	if _, err := ledger.New(ledger.Hledger).Export(buf, account, withHold); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "2019-04-05 * Opening balance\n" +
+		"    Assets:Mono:UAH  = 1000.00 UAH\n" +
+		"    Equity:Opening-Balances\n\n" +
+		"2019-04-05 * Переказ  ; id:transfer\n" +
+		"    Assets:Mono:UAH  -50.00 UAH = 950.00 UAH\n" +
+		"    Expenses:Transfers  50.00 UAH\n\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+
+	buf.Reset()
+
+	if _, err := ledger.New(ledger.Beancount).Export(buf, account, withHold); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte("2019-04-06 balance Assets:Mono:UAH  950.00 UAH")) {
+		t.Fatalf("Balance assertion must not include the hold:\n%s", buf)
+	}
+}
+
+func TestExport_ForeignCommission(t *testing.T) {
+	// The commission is in the account currency and is included in the amount.
+	yen := mono.StatementItem{
+		ID:                  "yen",
+		Time:                mono.Time(1554466347),
+		Description:         "Tokyo Store",
+		MCC:                 5999,
+		Amount:              -31234,
+		OperationAmount:     -1100,
+		CurrencyCodeISO4217: 392,
+		CommissionRate:      1234,
+		Balance:             100000,
+	}
+
+	buf := &bytes.Buffer{}
+
+	if _, err := ledger.New(ledger.Hledger).Export(buf, account, []mono.StatementItem{yen}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "2019-04-05 * Tokyo Store  ; id:yen\n" +
+		"    Assets:Mono:UAH  -312.34 UAH = 1000.00 UAH\n" +
+		"    Expenses:Shopping  1100 JPY @@ 300.00 UAH\n" +
+		"    Expenses:Fees:Mono  12.34 UAH\n\n"
+
+	if !bytes.HasSuffix(buf.Bytes(), []byte(expected)) {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+}
+
+func TestExport_BeancountOpensOnce(t *testing.T) {
+	ids := ledger.IDs{}
+	e := ledger.New(ledger.Beancount, ledger.WithSeen(ids))
+	first := mono.StatementItem{ID: "a", Time: 1554466347, MCC: 5411, Amount: -100, Balance: 900}
+	second := mono.StatementItem{ID: "b", Time: 1554566347, MCC: 5411, Amount: -100, Balance: 800}
+
+	buf := &bytes.Buffer{}
+
+	if _, err := e.Export(buf, account, []mono.StatementItem{first}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ids.Has("open:Assets:Mono:UAH") || !ids.Has("open:Expenses:Groceries") {
+		t.Fatalf("Expected opened accounts to be seen: %v", ids)
+	}
+
+	buf.Reset()
+
+	if _, err := e.Export(buf, account, []mono.StatementItem{second, first}); err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(buf.Bytes(), []byte(" open ")) {
+		t.Fatalf("Expected accounts to be opened once:\n%s", buf)
+	}
+}
+
+func TestExport_BeancountNumericCommodity(t *testing.T) {
+	item := mono.StatementItem{ID: "x", Time: 1554466347, Amount: -100, OperationAmount: -5, CurrencyCodeISO4217: 999}
+
+	_, err := ledger.New(ledger.Beancount).Export(&bytes.Buffer{}, account, []mono.StatementItem{item})
+	if err == nil || err.Error() != "currency 999 has no alphabetic code for beancount" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := ledger.New(ledger.Hledger).Export(&bytes.Buffer{}, account, []mono.StatementItem{item}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExport_Defaults(t *testing.T) {
+	buf := &bytes.Buffer{}
+	income := mono.StatementItem{ID: "income", Time: 1554466347, Description: "Зарплата", MCC: 4829, Amount: 100}
+
+	if _, err := ledger.New(ledger.Hledger).Export(buf, account, []mono.StatementItem{income}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "2019-04-05 * Opening balance\n" +
+		"    Assets:Mono:UAH  = -1.00 UAH\n" +
+		"    Equity:Opening-Balances\n\n" +
+		"2019-04-05 * Зарплата  ; id:income\n" +
+		"    Assets:Mono:UAH  1.00 UAH = 0.00 UAH\n" +
+		"    Income:Transfers  -1.00 UAH\n\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+
+	if _, err := ledger.New(42).Export(buf, account, nil); err == nil || err.Error() != "unknown format 42" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestIDs_SaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "exported.ids")
+
+	ids, err := ledger.LoadIDs(path)
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("Expected the missing file to be the empty set, got %v: %v", ids, err)
+	}
+
+	ids.Add("b")
+	ids.Add("a")
+
+	if err := ids.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	bts, err := ioutil.ReadFile(path)
+	if err != nil || string(bts) != "a\nb\n" {
+		t.Fatalf("Unexpected file %q: %v", bts, err)
+	}
+
+	loaded, err := ledger.LoadIDs(path)
+	if err != nil || !loaded.Has("a") || !loaded.Has("b") || len(loaded) != 2 {
+		t.Fatalf("Unexpected set %v: %v", loaded, err)
+	}
+}
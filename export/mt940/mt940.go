@@ -0,0 +1,327 @@
+// Package mt940 writes statements as SWIFT MT940 customer statement messages.
+//
+// MT940 allows only the SWIFT character set, so Ukrainian text is transliterated
+// and other characters are replaced. Holds are not booked yet and are skipped.
+// Without booked items, the statement has only the opening and closing balances.
+package mt940
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+const (
+	referenceLength = 16
+	narrativeLines  = 6
+	narrativeLength = 65
+)
+
+// Option allows to change default values for the writer.
+type Option func(*config)
+
+// WithReference sets the transaction reference of the message, field :20:.
+// Default is `MONO` followed by the date of the latest item.
+func WithReference(reference string) Option {
+	return func(c *config) {
+		c.reference = reference
+	}
+}
+
+// WithStatementNumber sets the statement number, field :28C:. Default is 1.
+func WithStatementNumber(number int) Option {
+	return func(c *config) {
+		c.number = number
+	}
+}
+
+// WithDate sets the date of balances of the statement without booked items.
+// Default is the time of the latest hold, or now when there are no items.
+func WithDate(date time.Time) Option {
+	return func(c *config) {
+		c.date = date
+	}
+}
+
+type config struct {
+	reference string
+	number    int
+	date      time.Time
+}
+
+// Write writes booked items of the account as the MT940 message.
+// Items can be in any order; transactions are written oldest first.
+// When no item is booked, the balance before holds, or the account balance without items, is written.
+func Write(w io.Writer, account mono.Account, items []mono.StatementItem, opts ...Option) error {
+	c := config{number: 1}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if len(account.IBAN) == 0 {
+		return errors.New("account IBAN must be set")
+	}
+
+	booked := make([]mono.StatementItem, 0, len(items))
+
+	for _, item := range items {
+		if !item.Hold {
+			booked = append(booked, item)
+		}
+	}
+
+	if len(booked) == 0 {
+		opening, date := balanceOnly(account, items, c.date)
+		return write(w, c, account, opening, date, date, nil)
+	}
+
+	sort.SliceStable(booked, func(i, j int) bool {
+		return booked[i].Time < booked[j].Time
+	})
+
+	first, last := booked[0], booked[len(booked)-1]
+
+	return write(w, c, account, openingBalance(items), first.Time.Time(), last.Time.Time(), booked)
+}
+
+// balanceOnly returns the balance and its date for the statement without booked items.
+// Holds are not booked, so the balance is the one before the oldest hold.
+func balanceOnly(account mono.Account, items []mono.StatementItem, date time.Time) (int64, time.Time) {
+	value := account.Balance
+
+	if len(items) > 0 {
+		latest := items[0]
+
+		for _, item := range items {
+			if item.Time > latest.Time {
+				latest = item
+			}
+		}
+
+		value = openingBalance(items)
+
+		if date.IsZero() {
+			date = latest.Time.Time()
+		}
+	}
+
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	return value, date
+}
+
+// openingBalance returns the balance before the oldest item of any kind.
+// Starting from the first booked item instead would count holds made before it as booked.
+// Items of the same second are chained by their balances to find the oldest one.
+func openingBalance(items []mono.StatementItem) int64 {
+	oldest := items[0]
+
+	for _, item := range items {
+		if item.Time < oldest.Time {
+			oldest = item
+		}
+	}
+
+	after := map[int64]bool{}
+
+	for _, item := range items {
+		if item.Time == oldest.Time {
+			after[item.Balance] = true
+		}
+	}
+
+	for _, item := range items {
+		if item.Time == oldest.Time && !after[item.Balance-item.Amount] {
+			return item.Balance - item.Amount
+		}
+	}
+
+	return oldest.Balance - oldest.Amount
+}
+
+// write writes the message with the opening balance and booked items, the oldest first.
+func write(
+	w io.Writer, c config, account mono.Account, opening int64, from, to time.Time, booked []mono.StatementItem,
+) error {
+	currency := account.CurrencyCodeISO4217
+	closing := opening
+
+	if len(c.reference) == 0 {
+		c.reference = "MONO" + to.UTC().Format("20060102")
+	}
+
+	lines := []string{
+		":20:" + truncate(sanitize(c.reference), referenceLength),
+		":25:" + account.IBAN,
+		fmt.Sprintf(":28C:%d/1", c.number),
+		":60F:" + balance(opening, currency, from),
+	}
+
+	for _, item := range booked {
+		closing += item.Amount
+		lines = append(lines, transaction(item, currency)...)
+	}
+
+	lines = append(lines, ":62F:"+balance(closing, currency, to), "-")
+
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+
+	return err
+}
+
+// transaction makes the statement line :61: and the information to account owner :86:.
+func transaction(item mono.StatementItem, currency int) []string {
+	at := item.Time.Time().UTC()
+	code := "NMSC"
+
+	if mono.ClassifyMCC(item.MCC) == mono.CategoryTransfers {
+		code = "NTRF"
+	}
+
+	reference := truncate(sanitize(item.ID), referenceLength)
+	line := ":61:" + at.Format("060102") + at.Format("0102") + mark(item.Amount) + amount(item.Amount, currency) +
+		code + reference + "//" + reference
+
+	lines := []string{line}
+
+	if item.CurrencyCodeISO4217 != currency && item.OperationAmount != 0 {
+		original := mono.CurrencyAlpha(item.CurrencyCodeISO4217) + amount(item.OperationAmount, item.CurrencyCodeISO4217)
+		lines = append(lines, "/OCMT/"+original+"/")
+	}
+
+	return append(lines, narrative(item)...)
+}
+
+// narrative makes :86: with the description, the counterparty and the comment split into lines.
+func narrative(item mono.StatementItem) []string {
+	parts := []string{sanitize(item.Description)}
+
+	if len(item.CounterName) > 0 {
+		parts = append(parts, "/NAME/"+sanitize(item.CounterName))
+	}
+
+	if len(item.CounterIBAN) > 0 {
+		parts = append(parts, "/IBAN/"+sanitize(item.CounterIBAN))
+	}
+
+	if len(item.CounterEdrpou) > 0 {
+		parts = append(parts, "/EDRPOU/"+sanitize(item.CounterEdrpou))
+	}
+
+	if len(item.Comment) > 0 {
+		parts = append(parts, "/REMI/"+sanitize(item.Comment))
+	}
+
+	text := strings.TrimSpace(strings.Join(parts, " "))
+	if len(text) == 0 {
+		return nil
+	}
+
+	var lines []string
+
+	for i := 0; i < len(text) && len(lines) < narrativeLines; i += narrativeLength {
+		end := i + narrativeLength
+		if end > len(text) {
+			end = len(text)
+		}
+
+		chunk := text[i:end]
+		if strings.HasPrefix(chunk, ":") || strings.HasPrefix(chunk, "-") {
+			chunk = "." + chunk[1:]
+		}
+
+		lines = append(lines, chunk)
+	}
+
+	lines[0] = ":86:" + lines[0]
+
+	return lines
+}
+
+func balance(value int64, currency int, at time.Time) string {
+	return mark(value) + at.UTC().Format("060102") + mono.CurrencyAlpha(currency) + amount(value, currency)
+}
+
+func mark(value int64) string {
+	if value < 0 {
+		return "D"
+	}
+
+	return "C"
+}
+
+// amount formats the absolute value with the comma as the decimal separator, which is mandatory in MT940.
+func amount(value int64, currency int) string {
+	formatted := strings.TrimPrefix(mono.FormatAmount(value, currency), "-")
+	if !strings.Contains(formatted, ".") {
+		return formatted + ","
+	}
+
+	return strings.Replace(formatted, ".", ",", 1)
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	return s[:max]
+}
+
+// sanitize transliterates Ukrainian letters and replaces characters outside of the SWIFT set with dots.
+func sanitize(s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("/-?:().,'+ ", r):
+			sb.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			sb.WriteRune(' ')
+		default:
+			if latin, ok := transliterate(r); ok {
+				sb.WriteString(latin)
+			} else {
+				sb.WriteRune('.')
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// transliterate follows the official Ukrainian transliteration of 2010,
+// using the word-initial forms of letters everywhere.
+func transliterate(r rune) (string, bool) {
+	lower := []rune("абвгґдеєжзиіїйклмнопрстуфхцчшщьюяʼ’ыэёъ")
+	latin := []string{
+		"a", "b", "v", "h", "g", "d", "e", "ye", "zh", "z", "y", "i", "yi", "y", "k", "l", "m", "n", "o", "p", "r",
+		"s", "t", "u", "f", "kh", "ts", "ch", "sh", "shch", "", "yu", "ya", "", "", "y", "e", "yo", "",
+	}
+
+	upper := []rune(strings.ToUpper(string(lower)))
+
+	for i := range lower {
+		if r == lower[i] {
+			return latin[i], true
+		}
+
+		if r == upper[i] && len(latin[i]) > 0 {
+			return strings.ToUpper(latin[i][:1]) + latin[i][1:], true
+		}
+
+		if r == upper[i] {
+			return "", true
+		}
+	}
+
+	return "", false
+}
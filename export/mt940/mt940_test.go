@@ -0,0 +1,164 @@
+package mt940_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/export/mt940"
+)
+
+var account = mono.Account{ID: "acc", IBAN: "UA733220010000026201234567890", CurrencyCodeISO4217: 980}
+
+// items are in the bank's order, the latest first.
+var items = []mono.StatementItem{{
+	ID:          "hold",
+	Time:        mono.Time(1554566347),
+	Description: "АТБ",
+	MCC:         5411,
+	Amount:      -1000,
+	Balance:     9948500,
+	Hold:        true,
+}, {
+	ID:            "transfer",
+	Time:          mono.Time(1554470000),
+	Description:   "Переказ на рахунок",
+	CounterName:   "ТОВ \"Ромашка\"",
+	CounterIBAN:   "UA213223130000026007233566001",
+	CounterEdrpou: "12345678",
+	Comment:       "Оплата за рахунком №42 за послуги з розробки програмного забезпечення за березень",
+	MCC:           4829,
+	Amount:        -100500,
+	Balance:       9949500,
+}, {
+	ID:                  "ZuHWzqkKGVo=",
+	Time:                mono.Time(1554466347),
+	Description:         "Amazon",
+	MCC:                 5999,
+	Amount:              -95000,
+	OperationAmount:     -3500,
+	CurrencyCodeISO4217: 840,
+	Balance:             10050000,
+}}
+
+const expected = ":20:MONO20190405\r\n" +
+	":25:UA733220010000026201234567890\r\n" +
+	":28C:7/1\r\n" +
+	":60F:C190405UAH101450,00\r\n" +
+	":61:1904050405D950,00NMSCZuHWzqkKGVo.//ZuHWzqkKGVo.\r\n" +
+	"/OCMT/USD35,00/\r\n" +
+	":86:Amazon\r\n" +
+	":61:1904050405D1005,00NTRFtransfer//transfer\r\n" +
+	":86:Perekaz na rakhunok /NAME/TOV .Romashka. /IBAN/UA2132231300000260\r\n" +
+	"07233566001 /EDRPOU/12345678 /REMI/Oplata za rakhunkom .42 za pos\r\n" +
+	"luhy z rozrobky prohramnoho zabezpechennya za berezen\r\n" +
+	":62F:C190405UAH99495,00\r\n" +
+	"-\r\n"
+
+func TestWrite(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := mt940.Write(buf, account, items, mt940.WithStatementNumber(7)); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+}
+
+func TestWrite_Syntax(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := mt940.Write(buf, account, items, mt940.WithReference("Виписка за квітень")); err != nil {
+		t.Fatal(err)
+	}
+
+	charset := regexp.MustCompile(`^[a-zA-Z0-9/\-?:().,'+ ]*$`)
+	fields := map[string]*regexp.Regexp{
+		":20:":  regexp.MustCompile(`^:20:.{1,16}$`),
+		":25:":  regexp.MustCompile(`^:25:.{1,35}$`),
+		":28C:": regexp.MustCompile(`^:28C:\d{1,5}(/\d{1,5})?$`),
+		":60F:": regexp.MustCompile(`^:60F:[CD]\d{6}[A-Z]{3}[\d,]{1,15}$`),
+		":61:":  regexp.MustCompile(`^:61:\d{6}(\d{4})?R?[CD][A-Z]?[\d,]{1,15}[NF][A-Z0-9]{3}.{1,16}(//.{1,16})?$`),
+		":86:":  regexp.MustCompile(`^:86:.{1,65}$`),
+		":62F:": regexp.MustCompile(`^:62F:[CD]\d{6}[A-Z]{3}[\d,]{1,15}$`),
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if lines[0] != ":20:Vypyska za kvite" || lines[len(lines)-1] != "-" {
+		t.Fatalf("Unexpected message bounds:\n%s", buf)
+	}
+
+	for _, line := range lines {
+		if !charset.MatchString(line) || len(line) > 69 {
+			t.Fatalf("Line is outside of the SWIFT character set or too long: %q", line)
+		}
+
+		for prefix, re := range fields {
+			if strings.HasPrefix(line, prefix) && !re.MatchString(line) {
+				t.Fatalf("Field %s has invalid syntax: %q", prefix, line)
+			}
+		}
+	}
+}
+
+func TestWrite_Fail(t *testing.T) {
+	err := mt940.Write(&bytes.Buffer{}, mono.Account{ID: "acc"}, items)
+	if err == nil || err.Error() != "account IBAN must be set" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestWrite_NoBookedItems(t *testing.T) {
+	var holds bytes.Buffer
+	if err := mt940.Write(&holds, account, items[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ":20:MONO20190406\r\n" +
+		":25:UA733220010000026201234567890\r\n" +
+		":28C:1/1\r\n" +
+		":60F:C190406UAH99495,00\r\n" +
+		":62F:C190406UAH99495,00\r\n" +
+		"-\r\n"
+
+	if holds.String() != expected {
+		t.Fatalf("Unexpected output:\n%q", holds.String())
+	}
+
+	var empty bytes.Buffer
+
+	withBalance := account
+	withBalance.Balance = -2500
+
+	err := mt940.Write(&empty, withBalance, nil, mt940.WithDate(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(empty.String(), ":60F:D200102UAH25,00\r\n:62F:D200102UAH25,00\r\n-\r\n") {
+		t.Fatalf("Unexpected output:\n%q", empty.String())
+	}
+}
+
+func TestWrite_HoldBeforeBooked(t *testing.T) {
+	buf := &bytes.Buffer{}
+	items := []mono.StatementItem{
+		{ID: "salary", Time: mono.Time(1554566347), Amount: 5000, Balance: 14000},
+		{ID: "taxi", Time: mono.Time(1554470000), Amount: -100, Balance: 9000},
+		{ID: "hold", Time: mono.Time(1554466347), Amount: -900, Balance: 9100, Hold: true},
+	}
+
+	if err := mt940.Write(buf, account, items); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, ":60F:C190405UAH100,00\r\n") || !strings.Contains(out, ":62F:C190406UAH149,00\r\n") {
+		t.Fatalf("Opening balance must not include the hold:\n%s", buf)
+	}
+}
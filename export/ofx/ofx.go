@@ -0,0 +1,255 @@
+// Package ofx writes statements as OFX bank statement responses,
+// which personal-finance apps like GnuCash and Moneydance import.
+//
+// Both OFX 2.x XML and OFX 1.x SGML are supported:
+//
+//	err := ofx.Write(file, account, items, ofx.WithVersion(ofx.Version1))
+package ofx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Version is the OFX specification version.
+type Version int
+
+const (
+	// Version1 is OFX 1.0.2, the SGML format.
+	Version1 Version = 102
+	// Version2 is OFX 2.2.0, the XML format.
+	Version2 Version = 220
+
+	// MonobankID is the bank code (MFO) of Monobank, used when it cannot be taken from the IBAN.
+	MonobankID = "322001"
+
+	maxNameLength = 32
+)
+
+// Option allows to change default values for the writer.
+type Option func(*writer)
+
+// WithVersion sets the OFX version. Default is `Version2`.
+func WithVersion(version Version) Option {
+	return func(w *writer) {
+		w.version = version
+	}
+}
+
+// WithServerTime sets the time of the response.
+// Default is the current time. Set it to get the reproducible output.
+func WithServerTime(t time.Time) Option {
+	return func(w *writer) {
+		w.now = t
+	}
+}
+
+type writer struct {
+	version Version
+	now     time.Time
+}
+
+// node is the OFX element. Elements with children are aggregates, the rest are leaves with values.
+type node struct {
+	name     string
+	value    string
+	children []node
+}
+
+func leaf(name, value string) node {
+	return node{name: name, value: value}
+}
+
+func aggregate(name string, children ...node) node {
+	return node{name: name, children: children}
+}
+
+// Write writes items of the account as the OFX bank statement response.
+//
+// The account's IBAN becomes ACCTID, each item becomes STMTTRN with FITID equal to its ID,
+// and the balance of the latest item becomes the ledger balance.
+func Write(w io.Writer, account mono.Account, items []mono.StatementItem, opts ...Option) error {
+	wr := writer{version: Version2, now: time.Now()}
+
+	for _, opt := range opts {
+		opt(&wr)
+	}
+
+	if wr.version != Version1 && wr.version != Version2 {
+		return errors.New("unsupported OFX version " + strconv.Itoa(int(wr.version)))
+	}
+
+	if len(account.IBAN) == 0 {
+		return errors.New("account IBAN must be set")
+	}
+
+	sorted := append([]mono.StatementItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time < sorted[j].Time
+	})
+
+	doc := aggregate("OFX",
+		aggregate("SIGNONMSGSRSV1", aggregate("SONRS",
+			status(),
+			leaf("DTSERVER", formatTime(wr.now)),
+			leaf("LANGUAGE", "UKR"),
+		)),
+		aggregate("BANKMSGSRSV1", aggregate("STMTTRNRS",
+			leaf("TRNUID", "0"),
+			status(),
+			wr.statement(account, sorted),
+		)),
+	)
+
+	var sb strings.Builder
+
+	sb.WriteString(wr.header())
+	wr.write(&sb, doc, 0)
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func (wr writer) statement(account mono.Account, items []mono.StatementItem) node {
+	currency := account.CurrencyCodeISO4217
+	start, end := wr.now, wr.now
+	balance, asOf := account.Balance, wr.now
+
+	if len(items) > 0 {
+		start, end = items[0].Time.Time(), items[len(items)-1].Time.Time()
+		balance, asOf = items[len(items)-1].Balance, end
+	}
+
+	transactions := []node{leaf("DTSTART", formatTime(start)), leaf("DTEND", formatTime(end))}
+	for _, item := range items {
+		transactions = append(transactions, transaction(item, currency))
+	}
+
+	return aggregate("STMTRS",
+		leaf("CURDEF", mono.CurrencyAlpha(currency)),
+		aggregate("BANKACCTFROM",
+			leaf("BANKID", bankID(account.IBAN)),
+			leaf("ACCTID", account.IBAN),
+			leaf("ACCTTYPE", "CHECKING"),
+		),
+		aggregate("BANKTRANLIST", transactions...),
+		aggregate("LEDGERBAL",
+			leaf("BALAMT", mono.FormatAmount(balance, currency)),
+			leaf("DTASOF", formatTime(asOf)),
+		),
+	)
+}
+
+func transaction(item mono.StatementItem, currency int) node {
+	trnType := "DEBIT"
+	if item.Amount > 0 {
+		trnType = "CREDIT"
+	}
+
+	children := []node{
+		leaf("TRNTYPE", trnType),
+		leaf("DTPOSTED", formatTime(item.Time.Time())),
+		leaf("TRNAMT", mono.FormatAmount(item.Amount, currency)),
+		leaf("FITID", item.ID),
+		leaf("NAME", truncate(item.Description, maxNameLength)),
+		leaf("MEMO", item.Description),
+	}
+
+	if item.CurrencyCodeISO4217 != currency && item.OperationAmount != 0 {
+		children = append(children, aggregate("ORIGCURRENCY",
+			leaf("CURRATE", rate(item, currency)),
+			leaf("CURSYM", mono.CurrencyAlpha(item.CurrencyCodeISO4217)),
+		))
+	}
+
+	return aggregate("STMTTRN", children...)
+}
+
+// rate is how many units of the account currency one unit of the transaction currency costs.
+func rate(item mono.StatementItem, currency int) string {
+	amount := float64(item.Amount) / math.Pow10(mono.CurrencyMinorUnits(currency))
+	operation := float64(item.OperationAmount) / math.Pow10(mono.CurrencyMinorUnits(item.CurrencyCodeISO4217))
+
+	return strconv.FormatFloat(math.Round(amount/operation*1e6)/1e6, 'f', -1, 64)
+}
+
+func status() node {
+	return aggregate("STATUS", leaf("CODE", "0"), leaf("SEVERITY", "INFO"))
+}
+
+func (wr writer) header() string {
+	if wr.version == Version1 {
+		return "OFXHEADER:100\n" +
+			"DATA:OFXSGML\n" +
+			"VERSION:102\n" +
+			"SECURITY:NONE\n" +
+			"ENCODING:UTF-8\n" +
+			"CHARSET:NONE\n" +
+			"COMPRESSION:NONE\n" +
+			"OLDFILEUID:NONE\n" +
+			"NEWFILEUID:NONE\n\n"
+	}
+
+	return `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+		`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+}
+
+// write serializes the node. SGML leaves have no closing tags, XML leaves do.
+func (wr writer) write(sb *strings.Builder, n node, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if n.children == nil {
+		sb.WriteString(indent + "<" + n.name + ">" + escape(n.value))
+
+		if wr.version == Version2 {
+			sb.WriteString("</" + n.name + ">")
+		}
+
+		sb.WriteString("\n")
+
+		return
+	}
+
+	sb.WriteString(indent + "<" + n.name + ">\n")
+
+	for _, child := range n.children {
+		wr.write(sb, child, depth+1)
+	}
+
+	sb.WriteString(indent + "</" + n.name + ">\n")
+}
+
+// bankID takes the bank code from the Ukrainian IBAN, where it follows the check digits.
+func bankID(iban string) string {
+	if strings.HasPrefix(iban, "UA") && len(iban) >= 10 {
+		return iban[4:10]
+	}
+
+	return MonobankID
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + fmt.Sprintf(".%03d", t.Nanosecond()/1e6) + "[0:GMT]"
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max])
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,185 @@
+package ofx_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/export/ofx"
+)
+
+var account = mono.Account{
+	ID:                  "acc",
+	IBAN:                "UA733220010000026201234567890",
+	Balance:             10049995,
+	CurrencyCodeISO4217: 980,
+}
+
+// items are in the bank's order, the latest first.
+var items = []mono.StatementItem{{
+	ID:                  "second",
+	Time:                mono.Time(1554470000),
+	Description:         "Зарплата & <бонус>",
+	MCC:                 4829,
+	Amount:              100000,
+	OperationAmount:     100000,
+	CurrencyCodeISO4217: 980,
+	Balance:             10149995,
+}, {
+	ID:                  "ZuHWzqkKGVo=",
+	Time:                mono.Time(1554466347),
+	Description:         "Дуже довгий опис покупки в іноземній валюті",
+	MCC:                 5411,
+	Amount:              -95000,
+	OperationAmount:     -3500,
+	CurrencyCodeISO4217: 840,
+	Balance:             10049995,
+}}
+
+var serverTime = time.Unix(1554480000, 0)
+
+const expectedV2 = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <SIGNONMSGSRSV1>
+    <SONRS>
+      <STATUS>
+        <CODE>0</CODE>
+        <SEVERITY>INFO</SEVERITY>
+      </STATUS>
+      <DTSERVER>20190405160000.000[0:GMT]</DTSERVER>
+      <LANGUAGE>UKR</LANGUAGE>
+    </SONRS>
+  </SIGNONMSGSRSV1>
+  <BANKMSGSRSV1>
+    <STMTTRNRS>
+      <TRNUID>0</TRNUID>
+      <STATUS>
+        <CODE>0</CODE>
+        <SEVERITY>INFO</SEVERITY>
+      </STATUS>
+      <STMTRS>
+        <CURDEF>UAH</CURDEF>
+        <BANKACCTFROM>
+          <BANKID>322001</BANKID>
+          <ACCTID>UA733220010000026201234567890</ACCTID>
+          <ACCTTYPE>CHECKING</ACCTTYPE>
+        </BANKACCTFROM>
+        <BANKTRANLIST>
+          <DTSTART>20190405121227.000[0:GMT]</DTSTART>
+          <DTEND>20190405131320.000[0:GMT]</DTEND>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20190405121227.000[0:GMT]</DTPOSTED>
+            <TRNAMT>-950.00</TRNAMT>
+            <FITID>ZuHWzqkKGVo=</FITID>
+            <NAME>Дуже довгий опис покупки в інозе</NAME>
+            <MEMO>Дуже довгий опис покупки в іноземній валюті</MEMO>
+            <ORIGCURRENCY>
+              <CURRATE>27.142857</CURRATE>
+              <CURSYM>USD</CURSYM>
+            </ORIGCURRENCY>
+          </STMTTRN>
+          <STMTTRN>
+            <TRNTYPE>CREDIT</TRNTYPE>
+            <DTPOSTED>20190405131320.000[0:GMT]</DTPOSTED>
+            <TRNAMT>1000.00</TRNAMT>
+            <FITID>second</FITID>
+            <NAME>Зарплата &amp; &lt;бонус&gt;</NAME>
+            <MEMO>Зарплата &amp; &lt;бонус&gt;</MEMO>
+          </STMTTRN>
+        </BANKTRANLIST>
+        <LEDGERBAL>
+          <BALAMT>101499.95</BALAMT>
+          <DTASOF>20190405131320.000[0:GMT]</DTASOF>
+        </LEDGERBAL>
+      </STMTRS>
+    </STMTTRNRS>
+  </BANKMSGSRSV1>
+</OFX>
+`
+
+func TestWrite_Version2(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := ofx.Write(buf, account, items, ofx.WithServerTime(serverTime)); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != expectedV2 {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+
+	dec := xml.NewDecoder(buf)
+
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Output is not the well-formed XML: %v", err)
+		}
+	}
+}
+
+func TestWrite_Version1(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := ofx.Write(buf, account, items[:1], ofx.WithVersion(ofx.Version1), ofx.WithServerTime(serverTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\n") || !strings.Contains(out, "\n\n<OFX>\n") {
+		t.Fatalf("Unexpected header:\n%s", out)
+	}
+
+	for _, expected := range []string{
+		"<TRNAMT>1000.00\n",
+		"<FITID>second\n",
+		"</STMTTRN>\n",
+		"<BALAMT>101499.95\n",
+		"</OFX>\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("Expected output to contain %q:\n%s", expected, out)
+		}
+	}
+
+	if strings.Contains(out, "</TRNAMT>") {
+		t.Fatalf("SGML leaves must not be closed:\n%s", out)
+	}
+}
+
+func TestWrite_NoItems(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := ofx.Write(buf, account, nil, ofx.WithServerTime(serverTime)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<BALAMT>100499.95</BALAMT>\n          <DTASOF>20190405160000.000[0:GMT]</DTASOF>"
+	if !strings.Contains(buf.String(), expected) {
+		t.Fatalf("Expected the account balance as of the server time:\n%s", buf)
+	}
+}
+
+func TestWrite_Fail(t *testing.T) {
+	err := ofx.Write(&bytes.Buffer{}, mono.Account{ID: "acc"}, items)
+	if err == nil || err.Error() != "account IBAN must be set" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = ofx.Write(&bytes.Buffer{}, account, items, ofx.WithVersion(103))
+	if err == nil || err.Error() != "unsupported OFX version 103" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
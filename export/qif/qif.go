@@ -0,0 +1,357 @@
+// Package qif writes and reads statements in Quicken Interchange Format.
+//
+// Each statement item becomes the transaction with the payee from the counterparty name or the description,
+// the memo from the comment, and the category from the MCC.
+// Commission and cashback are written as split lines, so the transaction total matches the account amount.
+package qif
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Split is the split line of the transaction.
+type Split struct {
+	Category string
+	Memo     string
+	// Amount in minor units of the account currency.
+	Amount int64
+}
+
+// Transaction is the single QIF record.
+type Transaction struct {
+	Date time.Time
+	// Amount in minor units of the account currency.
+	Amount   int64
+	Number   string
+	Payee    string
+	Memo     string
+	Category string
+	Cleared  bool
+	Splits   []Split
+}
+
+// Option allows to change default values for the writer and the reader.
+type Option func(*config)
+
+// WithDateFormat sets the date layout. Default is `01/02/2006`.
+func WithDateFormat(layout string) Option {
+	return func(c *config) {
+		c.dateFormat = layout
+	}
+}
+
+// WithLocation sets the time zone of dates. Default is UTC.
+func WithLocation(loc *time.Location) Option {
+	return func(c *config) {
+		c.loc = loc
+	}
+}
+
+// WithCurrency sets the currency of amounts for the reader. Default is UAH.
+// The writer takes the currency from the account.
+func WithCurrency(currency int) Option {
+	return func(c *config) {
+		c.currency = currency
+	}
+}
+
+// WithCategory sets how the MCC maps to the category. Default is `mono.ClassifyMCC`.
+func WithCategory(category func(mcc int) string) Option {
+	return func(c *config) {
+		c.category = category
+	}
+}
+
+// WithCommissionCategory sets the category of commission splits. Default is `Fees:Commission`.
+func WithCommissionCategory(category string) Option {
+	return func(c *config) {
+		c.commission = category
+	}
+}
+
+// WithCashback sets the income category of cashback splits and the account the cashback is transferred to.
+// Defaults are `Income:Cashback` and `Monobank Cashback`.
+func WithCashback(category, account string) Option {
+	return func(c *config) {
+		c.cashback = category
+		c.cashbackAccount = account
+	}
+}
+
+type config struct {
+	dateFormat      string
+	loc             *time.Location
+	currency        int
+	category        func(mcc int) string
+	commission      string
+	cashback        string
+	cashbackAccount string
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		dateFormat: "01/02/2006",
+		loc:        time.UTC,
+		currency:   980,
+		category: func(mcc int) string {
+			return string(mono.ClassifyMCC(mcc))
+		},
+		commission:      "Fees:Commission",
+		cashback:        "Income:Cashback",
+		cashbackAccount: "Monobank Cashback",
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// Writer writes statement items of the single account as QIF bank transactions.
+type Writer struct {
+	w       *bufio.Writer
+	config  config
+	started bool
+}
+
+// NewWriter creates the writer for items of the account.
+func NewWriter(w io.Writer, account mono.Account, opts ...Option) *Writer {
+	c := newConfig(opts)
+	c.currency = account.CurrencyCodeISO4217
+
+	return &Writer{w: bufio.NewWriter(w), config: c}
+}
+
+// Transaction converts the statement item to the QIF transaction.
+func (w *Writer) Transaction(item mono.StatementItem) Transaction {
+	tx := Transaction{
+		Date:     item.Time.Time().In(w.config.loc),
+		Amount:   item.Amount,
+		Number:   item.ID,
+		Payee:    item.Description,
+		Memo:     item.Comment,
+		Category: w.config.category(item.MCC),
+		Cleared:  !item.Hold,
+	}
+
+	if len(item.CounterName) > 0 {
+		tx.Payee = item.CounterName
+	}
+
+	if item.CommissionRate == 0 && item.CashbackAmount == 0 {
+		return tx
+	}
+
+	tx.Splits = []Split{{Category: tx.Category, Memo: item.Description, Amount: item.Amount + item.CommissionRate}}
+
+	if item.CommissionRate != 0 {
+		tx.Splits = append(tx.Splits, Split{Category: w.config.commission, Amount: -item.CommissionRate})
+	}
+
+	if item.CashbackAmount != 0 {
+		tx.Splits = append(tx.Splits,
+			Split{Category: w.config.cashback, Amount: item.CashbackAmount},
+			Split{Category: "[" + w.config.cashbackAccount + "]", Amount: -item.CashbackAmount},
+		)
+	}
+
+	return tx
+}
+
+// Write writes the statement item.
+// Call Flush to make sure the record reaches the underlying writer.
+func (w *Writer) Write(item mono.StatementItem) error {
+	return w.WriteTransaction(w.Transaction(item))
+}
+
+// WriteAll writes all items and flushes the writer.
+func (w *Writer) WriteAll(items []mono.StatementItem) error {
+	for _, item := range items {
+		if err := w.Write(item); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// WriteTransaction writes the transaction as is.
+func (w *Writer) WriteTransaction(tx Transaction) error {
+	var sb strings.Builder
+
+	if !w.started {
+		w.started = true
+
+		sb.WriteString("!Type:Bank\n")
+	}
+
+	line := func(code byte, value string) {
+		if len(value) > 0 {
+			sb.WriteString(string(code) + strings.ReplaceAll(value, "\n", " ") + "\n")
+		}
+	}
+
+	line('D', tx.Date.In(w.config.loc).Format(w.config.dateFormat))
+	line('T', mono.FormatAmount(tx.Amount, w.config.currency))
+
+	if tx.Cleared {
+		line('C', "*")
+	}
+
+	line('N', tx.Number)
+	line('P', tx.Payee)
+	line('M', tx.Memo)
+	line('L', tx.Category)
+
+	for _, split := range tx.Splits {
+		line('S', split.Category)
+		line('E', split.Memo)
+		line('$', mono.FormatAmount(split.Amount, w.config.currency))
+	}
+
+	sb.WriteString("^\n")
+
+	_, err := w.w.WriteString(sb.String())
+
+	return err
+}
+
+// Flush writes buffered records to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Read reads bank transactions.
+// Amounts are converted to minor units of the currency set with `WithCurrency`.
+func Read(r io.Reader, opts ...Option) ([]Transaction, error) {
+	c := newConfig(opts)
+	scanner := bufio.NewScanner(r)
+
+	var (
+		txs  []Transaction
+		tx   Transaction
+		n    int
+		open bool
+	)
+
+	for scanner.Scan() {
+		n++
+
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) == 0 {
+			continue
+		}
+
+		if line[0] == '!' {
+			if !strings.HasPrefix(line, "!Type:") && !strings.HasPrefix(line, "!Option:") {
+				return nil, fmt.Errorf("line %d: unsupported header %s", n, line)
+			}
+
+			continue
+		}
+
+		if line[0] == '^' {
+			txs = append(txs, tx)
+			tx, open = Transaction{}, false
+
+			continue
+		}
+
+		open = true
+
+		if err := c.field(&tx, line[0], line[1:]); err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read: %v", err)
+	}
+
+	if open {
+		return nil, errors.New("the last transaction is not terminated with ^")
+	}
+
+	return txs, nil
+}
+
+func (c config) field(tx *Transaction, code byte, value string) error {
+	var err error
+
+	switch code {
+	case 'D':
+		tx.Date, err = time.ParseInLocation(c.dateFormat, value, c.loc)
+	case 'T', 'U':
+		tx.Amount, err = parseAmount(value, mono.CurrencyMinorUnits(c.currency))
+	case 'C':
+		tx.Cleared = value == "*" || value == "X" || value == "c"
+	case 'N':
+		tx.Number = value
+	case 'P':
+		tx.Payee = value
+	case 'M':
+		tx.Memo = value
+	case 'L':
+		tx.Category = value
+	case 'S':
+		tx.Splits = append(tx.Splits, Split{Category: value})
+	case 'E', '$':
+		if len(tx.Splits) == 0 {
+			return errors.New("split field without the split category")
+		}
+
+		split := &tx.Splits[len(tx.Splits)-1]
+
+		if code == 'E' {
+			split.Memo = value
+		} else {
+			split.Amount, err = parseAmount(value, mono.CurrencyMinorUnits(c.currency))
+		}
+	}
+
+	return err
+}
+
+// parseAmount parses the decimal number to minor units without going through floats.
+func parseAmount(value string, minor int) (int64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+
+	whole, fraction := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		whole, fraction = value[:i], value[i+1:]
+	}
+
+	if len(fraction) > minor {
+		return 0, errors.New("amount has too many decimal places: " + value)
+	}
+
+	negative := strings.HasPrefix(whole, "-")
+	if negative || strings.HasPrefix(whole, "+") {
+		whole = whole[1:]
+	}
+
+	digits := whole + fraction
+	if len(digits) == 0 || strings.Trim(digits, "0123456789") != "" {
+		return 0, errors.New("invalid amount: " + value)
+	}
+
+	amount, err := strconv.ParseInt(digits+strings.Repeat("0", minor-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid amount: " + value)
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,214 @@
+package qif_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/export/qif"
+)
+
+var account = mono.Account{ID: "acc", CurrencyCodeISO4217: 980}
+
+var items = []mono.StatementItem{{
+	ID:             "ZuHWzqkKGVo=",
+	Time:           mono.Time(1554466347),
+	Description:    "Сільпо",
+	MCC:            5411,
+	Amount:         -95000,
+	CashbackAmount: 950,
+	Balance:        10050000,
+	Hold:           true,
+}, {
+	ID:             "transfer",
+	Time:           mono.Time(1554470000),
+	Description:    "Переказ на картку",
+	CounterName:    "Ольга П.",
+	Comment:        "За вечерю",
+	MCC:            4829,
+	Amount:         -100500,
+	CommissionRate: 500,
+	Balance:        9949500,
+}, {
+	ID:          "income",
+	Time:        mono.Time(1554480000),
+	Description: "Зарплата",
+	MCC:         4829,
+	Amount:      2000000,
+	Balance:     11949500,
+}}
+
+const expected = `!Type:Bank
+D04/05/2019
+T-950.00
+NZuHWzqkKGVo=
+PСільпо
+LGroceries
+SGroceries
+EСільпо
+$-950.00
+SIncome:Cashback
+$9.50
+S[Monobank Cashback]
+$-9.50
+^
+D04/05/2019
+T-1005.00
+C*
+Ntransfer
+PОльга П.
+MЗа вечерю
+LTransfers
+STransfers
+EПереказ на картку
+$-1000.00
+SFees:Commission
+$-5.00
+^
+D04/05/2019
+T20000.00
+C*
+Nincome
+PЗарплата
+LTransfers
+^
+`
+
+func TestWriter_WriteAll(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	if err := qif.NewWriter(buf, account).WriteAll(items); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+}
+
+func TestWriter_SplitsSumToTotal(t *testing.T) {
+	w := qif.NewWriter(&bytes.Buffer{}, account)
+
+	for _, item := range items {
+		tx := w.Transaction(item)
+		if len(tx.Splits) == 0 {
+			continue
+		}
+
+		var sum int64
+		for _, split := range tx.Splits {
+			sum += split.Amount
+		}
+
+		if sum != tx.Amount {
+			t.Fatalf("Splits of %s sum to %d, expected %d", item.ID, sum, tx.Amount)
+		}
+	}
+}
+
+func TestWriter_ForeignCommission(t *testing.T) {
+	// The commission is in the account currency and is included in the amount.
+	yen := mono.StatementItem{
+		ID:                  "yen",
+		Time:                mono.Time(1554466347),
+		Description:         "Tokyo Store",
+		MCC:                 5411,
+		Amount:              -31234,
+		OperationAmount:     -1100,
+		CurrencyCodeISO4217: 392,
+		CommissionRate:      1234,
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := qif.NewWriter(buf, account).WriteAll([]mono.StatementItem{yen}); err != nil {
+		t.Fatal(err)
+	}
+
+	splits := "T-312.34\nC*\nNyen\nPTokyo Store\nLGroceries\n" +
+		"SGroceries\nETokyo Store\n$-300.00\nSFees:Commission\n$-12.34\n^\n"
+
+	if !strings.HasSuffix(buf.String(), splits) {
+		t.Fatalf("Unexpected output:\n%s", buf)
+	}
+}
+
+func TestRead_RoundTrip(t *testing.T) {
+	kyiv := time.FixedZone("EEST", 3*60*60)
+	opts := []qif.Option{
+		qif.WithDateFormat("02.01.2006 15:04:05"),
+		qif.WithLocation(kyiv),
+		qif.WithCategory(func(mcc int) string { return "Expenses:" + string(mono.ClassifyMCC(mcc)) }),
+		qif.WithCommissionCategory("Expenses:Fees"),
+		qif.WithCashback("Income:Mono", "Assets:Cashback"),
+	}
+
+	buf := &bytes.Buffer{}
+	w := qif.NewWriter(buf, account, opts...)
+
+	if err := w.WriteAll(items); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := qif.Read(buf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actual) != len(items) {
+		t.Fatalf("Expected %d transactions, got %d", len(items), len(actual))
+	}
+
+	for i, item := range items {
+		expected := w.Transaction(item)
+
+		if !actual[i].Date.Equal(expected.Date) {
+			t.Fatalf("Date of %s did not round-trip: %v", item.ID, actual[i].Date)
+		}
+
+		actual[i].Date = expected.Date
+
+		if !reflect.DeepEqual(actual[i], expected) {
+			t.Fatalf("Transaction %s did not round-trip.\nActual:   %+v\nExpected: %+v", item.ID, actual[i], expected)
+		}
+	}
+}
+
+func TestRead_Currency(t *testing.T) {
+	actual, err := qif.Read(strings.NewReader("!Type:Bank\r\nD01/02/2020\r\nT-1,234\r\n^\r\n"), qif.WithCurrency(392))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actual) != 1 || actual[0].Amount != -1234 || actual[0].Date.Day() != 2 {
+		t.Fatalf("Unexpected transactions: %+v", actual)
+	}
+}
+
+func TestRead_Fail(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"!Account\n", "line 1: unsupported header !Account"},
+		{"!Type:Bank\nDx\n^\n", `line 2: parsing time "x" as "01/02/2006": cannot parse "x" as "01"`},
+		{"T1.234\n^\n", "line 1: amount has too many decimal places: 1.234"},
+		{"Tabc\n^\n", "line 1: invalid amount: abc"},
+		{"T--5\n^\n", "line 1: invalid amount: --5"},
+		{"T-\n^\n", "line 1: invalid amount: -"},
+		{"T+-5\n^\n", "line 1: invalid amount: +-5"},
+		{"T1.-5\n^\n", "line 1: invalid amount: 1.-5"},
+		{"$1.00\n^\n", "line 1: split field without the split category"},
+		{"T1.00\n", "the last transaction is not terminated with ^"},
+	}
+
+	for _, c := range cases {
+		_, err := qif.Read(strings.NewReader(c.input))
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("Unexpected error for %q: %v", c.input, err)
+		}
+	}
+}
@@ -1,9 +1,15 @@
 package mono_test
 
 import (
+	"bytes"
 	"errors"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
 )
 
 type clienttest struct {
@@ -18,6 +24,46 @@ func (c *clienttest) Do(req *http.Request) (*http.Response, error) {
 	return c.Resp, c.Err
 }
 
+type seqclienttest struct {
+	Reqs  []*http.Request
+	Resps []*http.Response
+	Errs  []error
+}
+
+func (c *seqclienttest) Do(req *http.Request) (*http.Response, error) {
+	i := len(c.Reqs)
+	c.Reqs = append(c.Reqs, req)
+
+	var err error
+	if i < len(c.Errs) {
+		err = c.Errs[i]
+	}
+
+	if i >= len(c.Resps) {
+		return nil, errors.New("no response for request #" + strconv.Itoa(i))
+	}
+
+	return c.Resps[i], err
+}
+
+func okResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func newPersonal(t *testing.T, token string, opts ...mono.Option) mono.Personal {
+	t.Helper()
+
+	personal, err := mono.NewPersonal(token, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return personal
+}
+
 type badReader struct {
 }
 
@@ -26,7 +26,18 @@ func (b badReader) Read([]byte) (int, error) {
 }
 
 func (b badReader) Close() error {
-	panic("reading failed already")
+	return nil
+}
+
+// closeTracker remembers whether the body was closed.
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
 }
 
 type badReadCloser struct {
@@ -40,6 +51,14 @@ func (b badReadCloser) Close() error {
 	return errors.New("boo")
 }
 
+type marshtest struct {
+	Err error
+}
+
+func (m marshtest) Marshal(interface{}) ([]byte, error) {
+	return nil, m.Err
+}
+
 type unmtest struct {
 	Err error
 }
@@ -0,0 +1,176 @@
+package mono
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// HoldEvent is the change in the hold lifecycle: HoldCreated, HoldSettled or HoldReversed.
+type HoldEvent interface {
+	holdEvent()
+}
+
+// HoldCreated tells the transaction was authorized and the amount is on hold.
+type HoldCreated struct {
+	Account string
+	Item    StatementItem
+}
+
+// HoldSettled tells the hold became the settled transaction.
+// AmountDelta is how much the settled amount differs from the held one, as the exchange rate may change.
+type HoldSettled struct {
+	Account     string
+	Item        StatementItem
+	AmountDelta int64
+}
+
+// HoldReversed tells the hold disappeared from the statement without settling.
+// Item is the last known state of the hold.
+type HoldReversed struct {
+	Account string
+	Item    StatementItem
+}
+
+func (HoldCreated) holdEvent()  {}
+func (HoldSettled) holdEvent()  {}
+func (HoldReversed) holdEvent() {}
+
+// HoldTracker follows holds across statement fetches and webhooks.
+// It remembers only pending holds, so settled and reversed transactions do not accumulate.
+// It is safe for concurrent use.
+type HoldTracker struct {
+	mu    sync.Mutex
+	holds map[string]map[string]StatementItem
+}
+
+// NewHoldTracker creates the tracker without known holds.
+func NewHoldTracker() *HoldTracker {
+	return &HoldTracker{holds: map[string]map[string]StatementItem{}}
+}
+
+// Observe compares the statement of the account for the period with the known holds.
+// Pending holds within the period that are missing from the statement are reversed.
+// The full page of MaxStatementItems may be cut at the oldest end, so then only holds made
+// at or after its oldest item are checked, and older ones wait for the fetch of the earlier period.
+func (t *HoldTracker) Observe(account string, from, to time.Time, items []StatementItem) []HoldEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := make(map[string]bool, len(items))
+	events := make([]HoldEvent, 0)
+	oldest := to
+
+	for _, item := range items {
+		seen[item.ID] = true
+
+		if at := item.Time.Time(); at.Before(oldest) {
+			oldest = at
+		}
+
+		if event := t.observe(account, item); event != nil {
+			events = append(events, event)
+		}
+	}
+
+	if len(items) >= MaxStatementItems && oldest.After(from) {
+		from = oldest
+	}
+
+	var reversed []StatementItem
+
+	for id, hold := range t.holds[account] {
+		at := hold.Time.Time()
+		if !seen[id] && !at.Before(from) && !at.After(to) {
+			delete(t.holds[account], id)
+			reversed = append(reversed, hold)
+		}
+	}
+
+	sortHolds(reversed)
+
+	for _, hold := range reversed {
+		events = append(events, HoldReversed{Account: account, Item: hold})
+	}
+
+	return events
+}
+
+// ObserveWebhook applies the transaction from the webhook.
+// Webhooks cannot tell about reversals, which are found by Observe only.
+func (t *HoldTracker) ObserveWebhook(data WebhookData) []HoldEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	events := make([]HoldEvent, 0, 1)
+	if event := t.observe(data.Data.AccountID, data.Data.StatementItem); event != nil {
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// Holds returns pending holds of the account, the latest first.
+func (t *HoldTracker) Holds(account string) []StatementItem {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	holds := make([]StatementItem, 0, len(t.holds[account]))
+	for _, hold := range t.holds[account] {
+		holds = append(holds, hold)
+	}
+
+	sortHolds(holds)
+
+	return holds
+}
+
+// Pending returns the sum of pending holds of the account.
+// The account balance already includes holds, so the settled balance is `Balance - Pending`.
+func (t *HoldTracker) Pending(account string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var sum int64
+	for _, hold := range t.holds[account] {
+		sum += hold.Amount
+	}
+
+	return sum
+}
+
+func (t *HoldTracker) observe(account string, item StatementItem) HoldEvent {
+	holds, ok := t.holds[account]
+	if !ok {
+		holds = map[string]StatementItem{}
+		t.holds[account] = holds
+	}
+
+	hold, known := holds[item.ID]
+
+	switch {
+	case item.Hold:
+		holds[item.ID] = item
+
+		if !known {
+			return HoldCreated{Account: account, Item: item}
+		}
+	case known:
+		delete(holds, item.ID)
+
+		return HoldSettled{Account: account, Item: item, AmountDelta: item.Amount - hold.Amount}
+	}
+
+	return nil
+}
+
+// sortHolds puts the latest first, ordering holds of the same second by ID.
+func sortHolds(holds []StatementItem) {
+	sort.Slice(holds, func(i, j int) bool {
+		if holds[i].Time != holds[j].Time {
+			return holds[i].Time > holds[j].Time
+		}
+
+		return holds[i].ID < holds[j].ID
+	})
+}
@@ -0,0 +1,92 @@
+package mono_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestHoldTracker(t *testing.T) {
+	tracker := mono.NewHoldTracker()
+	from, to := time.Unix(0, 0), time.Unix(1000, 0)
+
+	fx := mono.StatementItem{ID: "fx", Time: 300, Amount: -2700, Hold: true}
+	taxi := mono.StatementItem{ID: "taxi", Time: 200, Amount: -150, Hold: true}
+	salary := mono.StatementItem{ID: "salary", Time: 100, Amount: 100000}
+
+	events := tracker.Observe("acc", from, to, []mono.StatementItem{fx, taxi, salary})
+	expectDeepEquals(t, events, []mono.HoldEvent{
+		mono.HoldCreated{Account: "acc", Item: fx},
+		mono.HoldCreated{Account: "acc", Item: taxi},
+	})
+
+	expectDeepEquals(t, tracker.Holds("acc"), []mono.StatementItem{fx, taxi})
+	expectEquals(t, tracker.Pending("acc"), int64(-2850))
+
+	settled := fx
+	settled.Hold = false
+	settled.Amount = -2750
+
+	events = tracker.Observe("acc", from, to, []mono.StatementItem{settled, salary})
+	expectDeepEquals(t, events, []mono.HoldEvent{
+		mono.HoldSettled{Account: "acc", Item: settled, AmountDelta: -50},
+		mono.HoldReversed{Account: "acc", Item: taxi},
+	})
+
+	expectDeepEquals(t, tracker.Holds("acc"), []mono.StatementItem{})
+	expectEquals(t, tracker.Pending("acc"), int64(0))
+
+	events = tracker.Observe("acc", from, to, []mono.StatementItem{settled, salary})
+	expectDeepEquals(t, events, []mono.HoldEvent{})
+}
+
+func TestHoldTracker_OutsidePeriod(t *testing.T) {
+	tracker := mono.NewHoldTracker()
+	hold := mono.StatementItem{ID: "hold", Time: 100, Amount: -5, Hold: true}
+
+	tracker.Observe("acc", time.Unix(0, 0), time.Unix(200, 0), []mono.StatementItem{hold})
+
+	events := tracker.Observe("acc", time.Unix(150, 0), time.Unix(300, 0), nil)
+	expectDeepEquals(t, events, []mono.HoldEvent{})
+	expectDeepEquals(t, tracker.Holds("acc"), []mono.StatementItem{hold})
+	expectDeepEquals(t, tracker.Holds("other"), []mono.StatementItem{})
+}
+
+func TestHoldTracker_FullPage(t *testing.T) {
+	tracker := mono.NewHoldTracker()
+	old := mono.StatementItem{ID: "old", Time: 100, Amount: -5, Hold: true}
+	recent := mono.StatementItem{ID: "recent", Time: 800, Amount: -7, Hold: true}
+
+	tracker.Observe("acc", time.Unix(0, 0), time.Unix(1000, 0), []mono.StatementItem{recent, old})
+
+	page := make([]mono.StatementItem, mono.MaxStatementItems)
+	for i := range page {
+		page[i] = mono.StatementItem{ID: strconv.Itoa(i), Time: mono.Time(900 - i)}
+	}
+
+	events := tracker.Observe("acc", time.Unix(0, 0), time.Unix(1000, 0), page)
+	expectDeepEquals(t, events, []mono.HoldEvent{mono.HoldReversed{Account: "acc", Item: recent}})
+	expectDeepEquals(t, tracker.Holds("acc"), []mono.StatementItem{old})
+}
+
+func TestHoldTracker_Webhook(t *testing.T) {
+	tracker := mono.NewHoldTracker()
+	hold := mono.StatementItem{ID: "hold", Time: 100, Amount: -5, Hold: true}
+	data := mono.WebhookData{
+		Type: "StatementItem",
+		Data: mono.WebhookStatementItem{AccountID: "acc", StatementItem: hold},
+	}
+
+	expectDeepEquals(t, tracker.ObserveWebhook(data), []mono.HoldEvent{mono.HoldCreated{Account: "acc", Item: hold}})
+	expectDeepEquals(t, tracker.ObserveWebhook(data), []mono.HoldEvent{})
+
+	settled := hold
+	settled.Hold = false
+	data.Data.StatementItem = settled
+
+	expectDeepEquals(t, tracker.ObserveWebhook(data), []mono.HoldEvent{
+		mono.HoldSettled{Account: "acc", Item: settled},
+	})
+}
@@ -0,0 +1,132 @@
+package monocsv
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Matcher decides whether the imported item and the API item are the same transaction.
+type Matcher struct {
+	// Tolerance is the largest difference in time. The export may be made in the other time zone
+	// or round the seconds, so the default is two minutes.
+	Tolerance time.Duration
+	// Similarity is the smallest similarity of descriptions, from 0 to 1. Default is 0.5.
+	Similarity float64
+}
+
+// DefaultMatcher returns the matcher Merge uses.
+func DefaultMatcher() Matcher {
+	return Matcher{Tolerance: 2 * time.Minute, Similarity: 0.5}
+}
+
+// Match tells whether both items have the same amount, close times and similar descriptions.
+func (m Matcher) Match(imported, api mono.StatementItem) bool {
+	if imported.Amount != api.Amount {
+		return false
+	}
+
+	diff := imported.Time.Time().Sub(api.Time.Time())
+	if diff < -m.Tolerance || diff > m.Tolerance {
+		return false
+	}
+
+	return Similarity(imported.Description, api.Description) >= m.Similarity
+}
+
+// Merge returns API items along with imported ones that have no match among them, latest first.
+// Each API item matches at most one imported item.
+func Merge(api, imported []mono.StatementItem) []mono.StatementItem {
+	return DefaultMatcher().Merge(api, imported)
+}
+
+// Merge returns API items along with imported ones that have no match among them, latest first.
+// Each API item matches at most one imported item.
+func (m Matcher) Merge(api, imported []mono.StatementItem) []mono.StatementItem {
+	merged := make([]mono.StatementItem, 0, len(api)+len(imported))
+	merged = append(merged, api...)
+	used := make([]bool, len(api))
+
+	for _, item := range imported {
+		matched := false
+
+		for i := range api {
+			if !used[i] && m.Match(item, api[i]) {
+				used[i], matched = true, true
+
+				break
+			}
+		}
+
+		if !matched {
+			merged = append(merged, item)
+		}
+	}
+
+	sortLatestFirst(merged)
+
+	return merged
+}
+
+// Similarity compares descriptions by the Dice coefficient of their letter pairs,
+// ignoring case, punctuation and spacing. It is 1 for equal descriptions and 0 for unrelated ones.
+func Similarity(a, b string) float64 {
+	a, b = normalize(a), normalize(b)
+	if a == b {
+		return 1
+	}
+
+	pa, pb := pairs(a), pairs(b)
+	if len(pa) == 0 || len(pb) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, p := range pa {
+		counts[p]++
+	}
+
+	common := 0
+
+	for _, p := range pb {
+		if counts[p] > 0 {
+			counts[p]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(pa)+len(pb))
+}
+
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, s)
+}
+
+func pairs(s string) []string {
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return nil
+	}
+
+	out := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		out = append(out, string(runes[i:i+2]))
+	}
+
+	return out
+}
+
+func sortLatestFirst(items []mono.StatementItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Time > items[j].Time
+	})
+}
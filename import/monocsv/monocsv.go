@@ -0,0 +1,316 @@
+// Package monocsv reads statements exported as CSV from the Monobank app,
+// so the history from before the token existed can be merged with the API data:
+//
+//	imported, err := monocsv.Parse(file)
+//	if err != nil {
+//	  return err
+//	}
+//	items = monocsv.Merge(items, imported)
+//
+// Both Ukrainian and English headers are recognised. XLS and PDF exports are not supported,
+// as they carry the same data as CSV.
+package monocsv
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// IDPrefix starts identifiers of imported items, as the export has no transaction identifiers.
+const IDPrefix = "csv:"
+
+// TimeFormat is the layout of the operation time in the export.
+const TimeFormat = "02.01.2006 15:04:05"
+
+// Option allows to change default values for the parser.
+type Option func(*parser)
+
+// WithLocation sets the time zone the export was made in. Default is Europe/Kyiv, or local when unavailable.
+func WithLocation(loc *time.Location) Option {
+	return func(p *parser) {
+		p.loc = loc
+	}
+}
+
+// WithCurrency sets the card currency when the header does not name it. Default is 980.
+func WithCurrency(currency int) Option {
+	return func(p *parser) {
+		p.currency = currency
+	}
+}
+
+type column int
+
+const (
+	columnTime column = iota
+	columnDescription
+	columnMCC
+	columnAmount
+	columnOperationAmount
+	columnOperationCurrency
+	columnCommission
+	columnCashback
+	columnBalance
+)
+
+type parser struct {
+	loc      *time.Location
+	currency int
+	columns  map[column]int
+}
+
+// Parse reads the export and returns its items latest first, like the API does.
+func Parse(r io.Reader, opts ...Option) ([]mono.StatementItem, error) {
+	p := parser{currency: 980, columns: map[column]int{}}
+
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	if p.loc == nil {
+		p.loc = kyiv()
+	}
+
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\uFEFF" {
+		_, _ = buffered.Discard(3)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("export is empty")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.header(header); err != nil {
+		return nil, err
+	}
+
+	var items []mono.StatementItem
+
+	occurrences := map[string]int{}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(strings.Join(record, "")) == 0 {
+			continue
+		}
+
+		item, err := p.item(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		key := strconv.FormatInt(int64(item.Time), 10) + "|" + strconv.FormatInt(item.Amount, 10) + "|" + item.Description
+		item.ID = id(key, occurrences[key])
+		occurrences[key]++
+		items = append(items, item)
+	}
+
+	sortLatestFirst(items)
+
+	return items, nil
+}
+
+func (p *parser) header(header []string) error {
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		switch {
+		case hasPrefix(name, "дата", "date"):
+			p.columns[columnTime] = i
+		case hasPrefix(name, "деталі", "description"):
+			p.columns[columnDescription] = i
+		case name == "mcc":
+			p.columns[columnMCC] = i
+		case hasPrefix(name, "сума в валюті картки", "card currency amount"):
+			p.columns[columnAmount] = i
+
+			if code, ok := headerCurrency(name); ok {
+				p.currency = code
+			}
+		case hasPrefix(name, "сума в валюті операції", "operation amount"):
+			p.columns[columnOperationAmount] = i
+		case hasPrefix(name, "валюта", "operation currency"):
+			p.columns[columnOperationCurrency] = i
+		case hasPrefix(name, "сума комісій", "commission"):
+			p.columns[columnCommission] = i
+		case hasPrefix(name, "сума кешбеку", "cashback"):
+			p.columns[columnCashback] = i
+		case hasPrefix(name, "залишок", "balance"):
+			p.columns[columnBalance] = i
+		}
+	}
+
+	for _, c := range []column{columnTime, columnDescription, columnAmount} {
+		if _, ok := p.columns[c]; !ok {
+			return errors.New("export header misses time, description or amount")
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) item(record []string) (mono.StatementItem, error) {
+	value := func(c column) string {
+		i, ok := p.columns[c]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	var (
+		item mono.StatementItem
+		err  error
+	)
+
+	t, err := time.ParseInLocation(TimeFormat, value(columnTime), p.loc)
+	if err != nil {
+		return item, fmt.Errorf("failed to parse time: %w", err)
+	}
+
+	item.Time = mono.Time(t.Unix())
+	item.Description = value(columnDescription)
+	item.CurrencyCodeISO4217 = p.currency
+
+	if mcc := value(columnMCC); len(mcc) > 0 {
+		if item.MCC, err = strconv.Atoi(mcc); err != nil {
+			return item, fmt.Errorf("failed to parse mcc: %w", err)
+		}
+	}
+
+	if code, ok := mono.CurrencyCode(strings.ToUpper(value(columnOperationCurrency))); ok {
+		item.CurrencyCodeISO4217 = code
+	}
+
+	amounts := []struct {
+		column   column
+		currency int
+		dst      *int64
+	}{
+		{columnAmount, p.currency, &item.Amount},
+		{columnOperationAmount, item.CurrencyCodeISO4217, &item.OperationAmount},
+		{columnCommission, p.currency, &item.CommissionRate},
+		{columnCashback, p.currency, &item.CashbackAmount},
+		{columnBalance, p.currency, &item.Balance},
+	}
+
+	for _, a := range amounts {
+		if *a.dst, err = parseAmount(value(a.column), a.currency); err != nil {
+			return item, err
+		}
+	}
+
+	if _, ok := p.columns[columnOperationAmount]; !ok {
+		item.OperationAmount = item.Amount
+	}
+
+	return item, nil
+}
+
+// id derives the stable identifier from the time, amount and description, and how many items before it had them.
+// Importing the same export twice, or the longer export of the same period, yields the same items,
+// and equal purchases in the same second differ.
+func id(key string, occurrence int) string {
+	sum := sha256.Sum256([]byte(key + "|" + strconv.Itoa(occurrence)))
+
+	return IDPrefix + base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// parseAmount converts "-950.00" to minor units of the currency exactly. Dashes and empty values are zero.
+func parseAmount(s string, currency int) (int64, error) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(s)
+	if len(s) == 0 || s == "—" || s == "-" {
+		return 0, nil
+	}
+
+	digits, sign := s, ""
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+
+	whole, fraction := digits, ""
+	if dot := strings.IndexByte(digits, '.'); dot >= 0 {
+		whole, fraction = digits[:dot], digits[dot+1:]
+	}
+
+	if len(whole)+len(fraction) == 0 || strings.Trim(whole+fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("failed to parse amount %q", s)
+	}
+
+	units := mono.CurrencyMinorUnits(currency)
+	if len(strings.TrimRight(fraction, "0")) > units {
+		return 0, fmt.Errorf("failed to parse amount %q: more than %d decimal places", s, units)
+	}
+
+	if len(fraction) > units {
+		fraction = fraction[:units]
+	}
+
+	fraction += strings.Repeat("0", units-len(fraction))
+
+	v, err := strconv.ParseInt(sign+whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse amount: %w", err)
+	}
+
+	return v, nil
+}
+
+// headerCurrency reads the currency from headers like "Сума в валюті картки (UAH)".
+func headerCurrency(name string) (int, bool) {
+	start, end := strings.LastIndex(name, "("), strings.LastIndex(name, ")")
+	if start < 0 || end < start {
+		return 0, false
+	}
+
+	return mono.CurrencyCode(strings.ToUpper(strings.TrimSpace(name[start+1 : end])))
+}
+
+func hasPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func kyiv() *time.Location {
+	if loc, err := time.LoadLocation("Europe/Kyiv"); err == nil {
+		return loc
+	}
+
+	if loc, err := time.LoadLocation("Europe/Kiev"); err == nil {
+		return loc
+	}
+
+	return time.Local
+}
@@ -0,0 +1,201 @@
+package monocsv_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/import/monocsv"
+)
+
+var kyiv = time.FixedZone("EEST", 3*60*60)
+
+const export = "\uFEFF" + `"Дата i час операції","Деталі операції","MCC","Сума в валюті картки (UAH)",` +
+	`"Сума в валюті операції","Валюта","Курс","Сума комісій (UAH)","Сума кешбеку (UAH)","Залишок після операції"
+"05.04.2019 15:12:27","Покупка щастя","7997","-950.00","-35.00","USD","27.14","—","9.50","100500.00"
+"05.04.2019 16:13:20","Сільпо","5411","-0.05","-0.05","UAH","—","—","—","100499.95"
+`
+
+func TestParse(t *testing.T) {
+	items, err := monocsv.Parse(strings.NewReader(export), monocsv.WithLocation(kyiv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []mono.StatementItem{{
+		Time:                mono.Time(1554470000),
+		Description:         "Сільпо",
+		MCC:                 5411,
+		Amount:              -5,
+		OperationAmount:     -5,
+		CurrencyCodeISO4217: 980,
+		Balance:             10049995,
+	}, {
+		Time:                mono.Time(1554466347),
+		Description:         "Покупка щастя",
+		MCC:                 7997,
+		Amount:              -95000,
+		OperationAmount:     -3500,
+		CurrencyCodeISO4217: 840,
+		CashbackAmount:      950,
+		Balance:             10050000,
+	}}
+
+	if len(items) != len(expected) {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+
+	for i, item := range items {
+		if !strings.HasPrefix(item.ID, monocsv.IDPrefix) {
+			t.Fatalf("Unexpected ID: %s", item.ID)
+		}
+
+		item.ID = ""
+		if !reflect.DeepEqual(item, expected[i]) {
+			t.Fatalf("Unexpected item:\n%+v\n%+v", item, expected[i])
+		}
+	}
+
+	again, _ := monocsv.Parse(strings.NewReader(export), monocsv.WithLocation(kyiv))
+	if again[0].ID != items[0].ID || again[0].ID == again[1].ID {
+		t.Fatalf("IDs are not stable: %s %s %s", items[0].ID, again[0].ID, again[1].ID)
+	}
+}
+
+func TestParse_English(t *testing.T) {
+	in := `"Date and time","Description","MCC","Card currency amount, (USD)","Operation amount",` +
+		`"Operation currency","Exchange rate","Commission, (USD)","Cashback amount, (USD)","Balance"
+"01.02.2021 10:00:00","Amazon","5942","-12.34","-10.50","EUR","1.17","0.10","—","87.66"
+`
+
+	items, err := monocsv.Parse(strings.NewReader(in), monocsv.WithLocation(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := items[0]
+	if item.Amount != -1234 || item.OperationAmount != -1050 || item.CurrencyCodeISO4217 != 978 ||
+		item.CommissionRate != 10 || item.Balance != 8766 || item.Time != mono.Time(1612173600) {
+		t.Fatalf("Unexpected item: %+v", item)
+	}
+}
+
+func TestParse_Fail(t *testing.T) {
+	cases := []struct {
+		in  string
+		err string
+	}{
+		{"", "export is empty"},
+		{"a,b\n", "export header misses time, description or amount"},
+		{"Дата,Деталі,Сума в валюті картки (UAH)\nyesterday,x,1\n", "line 2: failed to parse time"},
+		{"Дата,Деталі,Сума в валюті картки (UAH)\n01.01.2020 00:00:00,x,one\n", "line 2: failed to parse amount"},
+		{"Дата,Деталі,MCC,Сума в валюті картки (UAH)\n01.01.2020 00:00:00,x,y,1\n", "line 2: failed to parse mcc"},
+		{"Дата,Деталі,Сума в валюті картки (UAH)\n01.01.2020 00:00:00,x,1.005\n", "line 2: failed to parse amount"},
+		{"Дата,Деталі,Сума в валюті картки (UAH)\n01.01.2020 00:00:00,x,.\n", "line 2: failed to parse amount"},
+		{"Дата,Деталі,Сума в валюті картки (UAH)\n01.01.2020 00:00:00,x,1e3\n", "line 2: failed to parse amount"},
+	}
+
+	for _, c := range cases {
+		_, err := monocsv.Parse(strings.NewReader(c.in))
+		if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+			t.Fatalf("Unexpected error for %q: %v", c.in, err)
+		}
+	}
+}
+
+func TestParse_ExactAmounts(t *testing.T) {
+	in := "Дата,Деталі,Сума в валюті картки (UAH)\n" +
+		"01.01.2020 10:00:00,Coffee,-0.29\n" +
+		"01.01.2020 10:00:00,Coffee,-0.29\n" +
+		"01.01.2020 09:00:00,Salary,90071992547409.93\n" +
+		"01.01.2020 08:00:00,Refund,+12.3\n"
+
+	items, err := monocsv.Parse(strings.NewReader(in), monocsv.WithLocation(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	amounts := []int64{items[0].Amount, items[1].Amount, items[2].Amount, items[3].Amount}
+	if !reflect.DeepEqual(amounts, []int64{-29, -29, 9007199254740993, 1230}) {
+		t.Fatalf("Unexpected amounts: %v", amounts)
+	}
+
+	if items[0].ID == items[1].ID {
+		t.Fatalf("Expected equal purchases in the same second to have different IDs, got %s", items[0].ID)
+	}
+}
+
+func TestParse_IDsStableAcrossExports(t *testing.T) {
+	header := "Дата,Деталі,Сума в валюті картки (UAH)\n"
+	older := "01.01.2020 10:00:00,Coffee,-0.29\n" +
+		"01.01.2020 10:00:00,Coffee,-0.29\n" +
+		"01.01.2020 09:00:00,Salary,100\n"
+
+	first, err := monocsv.Parse(strings.NewReader(header+older), monocsv.WithLocation(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	longer, err := monocsv.Parse(strings.NewReader(header+"02.01.2020 12:00:00,Taxi,-80\n"+older),
+		monocsv.WithLocation(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{longer[1].ID, longer[2].ID, longer[3].ID}
+	if !reflect.DeepEqual(ids, []string{first[0].ID, first[1].ID, first[2].ID}) {
+		t.Fatalf("Expected the same items to keep their IDs, got %v and %v", first, longer)
+	}
+}
+
+func TestParse_WrapsErrors(t *testing.T) {
+	_, err := monocsv.Parse(strings.NewReader("Дата,Деталі,Сума в валюті картки (UAH)\nyesterday,x,1\n"))
+
+	var parseErr *time.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected the time parse error, got %v", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	api := []mono.StatementItem{
+		{ID: "a", Time: 1000, Amount: -5, Description: "Сільпо"},
+		{ID: "b", Time: 900, Amount: -5, Description: "Сільпо"},
+	}
+
+	imported := []mono.StatementItem{
+		{ID: "csv:1", Time: 1060, Amount: -5, Description: "СІЛЬПО."},
+		{ID: "csv:2", Time: 905, Amount: -5, Description: "Сільпо"},
+		{ID: "csv:3", Time: 950, Amount: -5, Description: "Сільпо"},
+		{ID: "csv:4", Time: 1000, Amount: -6, Description: "Сільпо"},
+		{ID: "csv:5", Time: 100, Amount: -5, Description: "Сільпо"},
+		{ID: "csv:6", Time: 1000, Amount: -5, Description: "АТБ"},
+	}
+
+	var ids []string
+	for _, item := range monocsv.Merge(api, imported) {
+		ids = append(ids, item.ID)
+	}
+
+	expected := []string{"a", "csv:4", "csv:6", "csv:3", "b", "csv:5"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("Unexpected merge: %v", ids)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if s := monocsv.Similarity("Нова пошта", "НОВА ПОШТА!"); s != 1 {
+		t.Fatalf("Unexpected similarity: %f", s)
+	}
+
+	if s := monocsv.Similarity("Переказ на картку", "Переказ на картку Іван"); s < 0.5 || s >= 1 {
+		t.Fatalf("Unexpected similarity: %f", s)
+	}
+
+	if s := monocsv.Similarity("Сільпо", "АТБ"); s != 0 {
+		t.Fatalf("Unexpected similarity: %f", s)
+	}
+}
@@ -12,6 +12,11 @@ type Unmarshaller interface {
 	Unmarshal(bts []byte, v interface{}) error
 }
 
+// Marshaller allows to specify custom encoder for request bodies.
+type Marshaller interface {
+	Marshal(v interface{}) ([]byte, error)
+}
+
 // HTTPClient defines which methods the library uses from `http.Client`.
 // This also allows to unit-test the library.
 type HTTPClient interface {
@@ -19,29 +24,50 @@ type HTTPClient interface {
 }
 
 // Public is the client for accessing public API.
+// Methods take CallOptions to change the single call.
 type Public interface {
 	// Currency get basic list of currency.
 	// The bank refreshes this list once in a five minutes or less.
-	Currency(ctx context.Context) ([]CurrencyInfo, error)
+	Currency(ctx context.Context, opts ...CallOption) ([]CurrencyInfo, error)
 }
 
 // Personal is the client for accessing Personal API.
+// Methods calling the API take CallOptions to change the single call.
 type Personal interface {
 	// ClientInfo gets info about the client for whom the token belongs.
-	ClientInfo(ctx context.Context) (*UserInfo, error)
+	ClientInfo(ctx context.Context, opts ...CallOption) (*UserInfo, error)
 	// Statements gets transactions for the specified time period.
 	// The duration period can be 2682000 max, which is 31 days + 1 hour.
 	// The bank defines the limitation.
 	// This value is defined in the constant `MaxAllowedDuration`.
-	Statements(ctx context.Context, account string, from, to time.Time) ([]StatementItem, error)
+	Statements(ctx context.Context, account string, from, to time.Time, opts ...CallOption) ([]StatementItem, error)
+	// StreamStatements is `Statements` that decodes items one by one as the body arrives and passes them to fn,
+	// so the whole page is never held in memory. Items come in the order of the bank, the latest first.
+	// The error of fn stops the stream and is returned as is.
+	// Items are decoded with encoding/json regardless of WithUnmarshaller.
+	StreamStatements(
+		ctx context.Context, account string, from, to time.Time, fn func(StatementItem) error, opts ...CallOption,
+	) error
 	// LatestStatements is the shortcut for `Statements`, where the `to` value is the current moment.
-	LatestStatements(ctx context.Context, account string, from time.Time) ([]StatementItem, error)
+	LatestStatements(ctx context.Context, account string, from time.Time, opts ...CallOption) ([]StatementItem, error)
 	// SetWebhook sets the webhook.
-	SetWebhook(ctx context.Context, webhook string) error
+	// The URL is validated with `ValidateWebhookURL` before the request is sent.
+	// The empty URL still clears the webhook, but that use is deprecated in favour of `ClearWebhook`.
+	SetWebhook(ctx context.Context, webhook string, opts ...CallOption) error
+	// Webhook gets the currently registered webhook URL.
+	// It is the shortcut for `ClientInfo` and reads `UserInfo.WebHookURL`.
+	Webhook(ctx context.Context, opts ...CallOption) (string, error)
+	// ClearWebhook removes the webhook by setting the empty URL.
+	ClearWebhook(ctx context.Context, opts ...CallOption) error
+	// RotateWebhook switches the webhook to the new URL and returns the previous one.
+	// Before switching, it makes the same GET handshake the bank does
+	// and fails if the new endpoint does not answer with 200 OK.
+	RotateWebhook(ctx context.Context, webhook string, opts ...CallOption) (string, error)
 	// ParseWebhook is a func that allows to extract the webhook data from the request.
 	ParseWebhook(ctx context.Context, reader io.ReadCloser) (*WebhookData, error)
 	// ListenForWebhooks returns channel and handler func.
 	// The client needs to register the handler func.
 	// Client will start receiving webhooks on the channel once they arrive to the handler.
+	// The handler answers the bank's GET handshake with 200 OK.
 	ListenForWebhooks(ctx context.Context) (<-chan WebhookData, http.HandlerFunc)
 }
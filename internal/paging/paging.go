@@ -0,0 +1,78 @@
+// Package paging gets transactions of periods the bank does not return with the single statement call.
+package paging
+
+import (
+	"context"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Statements makes the single statement call for the period. It keeps within the rate limit on its own.
+type Statements func(ctx context.Context, from, to time.Time) ([]mono.StatementItem, error)
+
+// Fetch gets transactions of the period, splitting it into windows the bank accepts
+// and paging through windows with the full page. Items are the latest first.
+//
+// The next page ends at the time of the last item, so items sharing that second are not lost,
+// and items seen on the previous page are skipped.
+func Fetch(ctx context.Context, from, to time.Time, statements Statements) ([]mono.StatementItem, error) {
+	var items []mono.StatementItem
+
+	seen := map[string]bool{}
+
+	for _, w := range windows(from, to, mono.MaxAllowedDuration*time.Second) {
+		for windowTo := w[1]; ; {
+			page, err := statements(ctx, w[0], windowTo)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range page {
+				if !seen[item.ID] {
+					seen[item.ID] = true
+					items = append(items, item)
+				}
+			}
+
+			if len(page) < mono.MaxStatementItems {
+				break
+			}
+
+			windowTo = nextPageEnd(page, windowTo)
+			if windowTo.Before(w[0]) {
+				break
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// windows splits the period into consecutive windows no longer than max, the latest first.
+func windows(from, to time.Time, max time.Duration) [][2]time.Time {
+	var ws [][2]time.Time
+
+	for end := to; end.After(from); end = end.Add(-max - time.Second) {
+		start := end.Add(-max)
+		if start.Before(from) {
+			start = from
+		}
+
+		ws = append(ws, [2]time.Time{start, end})
+	}
+
+	return ws
+}
+
+// nextPageEnd returns the end of the period for the page after the full one: the time of its last item.
+// When the whole page is within one second, the page cannot move on inclusively and steps back the second,
+// as the bank returns nothing beyond the page for the same period.
+func nextPageEnd(page []mono.StatementItem, windowTo time.Time) time.Time {
+	last := page[len(page)-1].Time.Time()
+	if last.Unix() >= windowTo.Unix() {
+		return last.Add(-time.Second)
+	}
+
+	return last
+}
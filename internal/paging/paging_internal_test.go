@@ -0,0 +1,33 @@
+package paging
+
+import (
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestWindows(t *testing.T) {
+	from := time.Unix(0, 0)
+	max := mono.MaxAllowedDuration * time.Second
+
+	ws := windows(from, from.Add(max), max)
+	if len(ws) != 1 || !ws[0][0].Equal(from) {
+		t.Fatalf("Unexpected windows: %v", ws)
+	}
+
+	ws = windows(from, from.Add(2*max+time.Hour), max)
+	if len(ws) != 3 || !ws[2][0].Equal(from) {
+		t.Fatalf("Unexpected windows: %v", ws)
+	}
+
+	for i, w := range ws {
+		if w[1].Sub(w[0]) > max {
+			t.Fatalf("Window %d is too long: %v", i, w)
+		}
+
+		if i > 0 && !ws[i-1][0].After(w[1]) {
+			t.Fatalf("Window %d overlaps the previous one: %v", i, w)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package paging_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/internal/paging"
+)
+
+// bank returns items of the period the latest first, no more than the page, like the bank does.
+func bank(items []mono.StatementItem, calls *int) paging.Statements {
+	return func(_ context.Context, from, to time.Time) ([]mono.StatementItem, error) {
+		*calls++
+
+		var page []mono.StatementItem
+
+		for _, item := range items {
+			if item.Time >= mono.Time(from.Unix()) && item.Time <= mono.Time(to.Unix()) &&
+				len(page) < mono.MaxStatementItems {
+				page = append(page, item)
+			}
+		}
+
+		return page, nil
+	}
+}
+
+func TestFetch_PageBoundaryInSameSecond(t *testing.T) {
+	now := time.Unix(1577836800, 0)
+	items := make([]mono.StatementItem, 0, 510)
+
+	for i := 0; i < 510; i++ {
+		at := now.Add(-time.Duration(i) * time.Minute)
+		if i >= 490 {
+			at = now.Add(-490 * time.Minute)
+		}
+
+		items = append(items, mono.StatementItem{ID: strconv.Itoa(i), Time: mono.Time(at.Unix())})
+	}
+
+	calls := 0
+
+	fetched, err := paging.Fetch(context.Background(), now.AddDate(0, 0, -1), now, bank(items, &calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fetched) != 510 || fetched[509].ID != "509" || calls != 2 {
+		t.Fatalf("Expected all 510 items once with two calls, got %d with %d calls", len(fetched), calls)
+	}
+}
+
+func TestFetch_FullPageInOneSecond(t *testing.T) {
+	now := time.Unix(1577836800, 0)
+	items := make([]mono.StatementItem, 0, 501)
+
+	for i := 0; i < 500; i++ {
+		items = append(items, mono.StatementItem{ID: strconv.Itoa(i), Time: mono.Time(now.Unix())})
+	}
+
+	items = append(items, mono.StatementItem{ID: "older", Time: mono.Time(now.Add(-time.Minute).Unix())})
+	calls := 0
+
+	fetched, err := paging.Fetch(context.Background(), now.Add(-time.Hour), now, bank(items, &calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fetched) != 501 || fetched[500].ID != "older" || calls != 2 {
+		t.Fatalf("Expected to step past the full second, got %d items with %d calls", len(fetched), calls)
+	}
+}
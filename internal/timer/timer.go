@@ -0,0 +1,20 @@
+// Package timer holds the waiting helper shared by the client, the syncer and the command-line tool.
+package timer
+
+import (
+	"context"
+	"time"
+)
+
+// Sleep waits for the duration or until the context is done.
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
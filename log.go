@@ -0,0 +1,179 @@
+package mono
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Redacted replaces tokens and personal data in log records.
+const Redacted = "[REDACTED]"
+
+// LogRecord is the structured record of the single API request.
+// Tokens never appear in it, and account IDs and IBANs are masked.
+type LogRecord struct {
+	Endpoint string
+	Method   string
+	// URL is the request URL with the account masked.
+	URL        string
+	StatusCode int
+	Duration   time.Duration
+	// Attempt is the number of the request within the call, starting with 1.
+	Attempt       int
+	RequestBytes  int
+	ResponseBytes int
+	// ErrorKind is empty when the request succeeded.
+	ErrorKind ErrorKind
+	Error     string
+	// RequestBody and ResponseBody are scrubbed bodies, set only for loggers added with WithBodyLogger.
+	RequestBody  string
+	ResponseBody string
+}
+
+// Logger receives the record for every request the client makes.
+type Logger interface {
+	Log(ctx context.Context, record LogRecord)
+}
+
+// LoggerFunc allows to use the function as Logger.
+type LoggerFunc func(ctx context.Context, record LogRecord)
+
+// Log calls the function.
+func (f LoggerFunc) Log(ctx context.Context, record LogRecord) {
+	f(ctx, record)
+}
+
+// logMiddleware wraps the transport, so every attempt is logged as it was sent.
+func (c tinyClient) logMiddleware(next Handler) Handler {
+	return func(ctx context.Context, call *Call) error {
+		started := time.Now()
+		err := next(ctx, call)
+
+		record := LogRecord{
+			Endpoint:      call.Endpoint,
+			Method:        call.Method,
+			URL:           maskURL(call),
+			StatusCode:    call.Response.StatusCode,
+			Duration:      time.Since(started),
+			Attempt:       call.Attempt,
+			RequestBytes:  len(call.requestBody),
+			ResponseBytes: int(call.responseBytes),
+		}
+
+		if err != nil {
+			record.ErrorKind = ErrorKindTransport
+			record.Error = redactToken(err.Error(), call.token)
+
+			var monoErr *Error
+			if errors.As(err, &monoErr) {
+				record.ErrorKind = monoErr.Kind
+			}
+		}
+
+		if c.logBodies {
+			if len(call.requestBody) > 0 {
+				record.RequestBody = redactToken(string(ScrubJSON(call.requestBody)), call.token)
+			}
+
+			if len(call.Response.Body) > 0 {
+				record.ResponseBody = redactToken(string(ScrubJSON(call.Response.Body)), call.token)
+			}
+		}
+
+		c.logger.Log(ctx, record)
+
+		return err
+	}
+}
+
+func redactToken(s, token string) string {
+	if len(token) == 0 {
+		return s
+	}
+
+	return strings.ReplaceAll(s, token, Redacted)
+}
+
+// ScrubJSON masks account IDs and IBANs and redacts other personal fields of the API objects,
+// like names, card numbers, balances, counterparties and comments.
+// Non-empty strings become Redacted and numbers become null, so the scrubbed body still decodes into the same types.
+// The body that is not JSON is redacted entirely.
+func ScrubJSON(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return []byte(Redacted)
+	}
+
+	bts, err := json.Marshal(scrubValue(v, ""))
+	if err != nil {
+		return []byte(Redacted)
+	}
+
+	return bts
+}
+
+func scrubValue(v interface{}, key string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, value := range t {
+			t[k] = scrubValue(value, k)
+		}
+
+		return t
+
+	case []interface{}:
+		for i, value := range t {
+			t[i] = scrubValue(value, key)
+		}
+
+		return t
+	}
+
+	switch key {
+	case "id", "iban", "account":
+		if s, ok := v.(string); ok {
+			return MaskID(s)
+		}
+
+		return nil
+	case "clientId", "name", "webHookUrl", "sendId", "maskedPan", "balance", "creditLimit",
+		"counterName", "counterIban", "counterEdrpou", "comment":
+		if s, ok := v.(string); ok {
+			if len(s) == 0 {
+				return s
+			}
+
+			return Redacted
+		}
+
+		return nil
+	}
+
+	return v
+}
+
+// MaskID hides the middle of the account ID or IBAN, keeping the first two and the last four characters.
+// Short values are hidden entirely.
+func MaskID(id string) string {
+	runes := []rune(id)
+	if len(runes) < 10 {
+		return strings.Repeat("*", len(runes))
+	}
+
+	return string(runes[:2]) + strings.Repeat("*", len(runes)-6) + string(runes[len(runes)-4:])
+}
+
+// maskURL masks the account the call is about in its URL.
+func maskURL(call *Call) string {
+	if len(call.Account) == 0 {
+		return call.URL
+	}
+
+	return strings.Replace(call.URL, "/"+call.Account+"/", "/"+MaskID(call.Account)+"/", 1)
+}
@@ -0,0 +1,98 @@
+package mono_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+type logtest struct {
+	Records []mono.LogRecord
+}
+
+func (l *logtest) Log(_ context.Context, record mono.LogRecord) {
+	l.Records = append(l.Records, record)
+}
+
+func TestWithLogger(t *testing.T) {
+	client := &clienttest{Resp: okResponse(`[{"id":"ZuHWzqkKGVo=","amount":-95000,"balance":10050000}]`)}
+	logger := &logtest{}
+
+	personal := newPersonal(t, "secret-token", mono.WithClient(client), mono.WithLogger(logger))
+
+	_, err := personal.Statements(context.Background(), "kKGVoZuHWzqVoZuH", time.Unix(1000, 0), time.Unix(2000, 0))
+	expectNoError(t, err)
+	expectEquals(t, len(logger.Records), 1)
+
+	record := logger.Records[0]
+	record.Duration = 0
+
+	expectDeepEquals(t, record, mono.LogRecord{
+		Endpoint:      mono.EndpointStatement,
+		Method:        http.MethodGet,
+		URL:           "https://api.monobank.ua/personal/statement/kK**********oZuH/1000/2000",
+		StatusCode:    http.StatusOK,
+		Attempt:       1,
+		ResponseBytes: 58,
+	})
+}
+
+func TestWithBodyLogger(t *testing.T) {
+	client := &seqclienttest{
+		Resps: []*http.Response{nil, okResponse(`{}`)},
+		Errs:  []error{errors.New("dial with secret-token failed")},
+	}
+	logger := &logtest{}
+
+	retry := func(next mono.Handler) mono.Handler {
+		return func(ctx context.Context, call *mono.Call) error {
+			if err := next(ctx, call); err == nil {
+				return nil
+			}
+
+			return next(ctx, call)
+		}
+	}
+
+	personal := newPersonal(t, "secret-token",
+		mono.WithClient(client), mono.WithBodyLogger(logger), mono.WithMiddleware(retry))
+
+	expectNoError(t, personal.SetWebhook(context.Background(), "https://domain/hook?key=secret"))
+	expectEquals(t, len(logger.Records), 2)
+
+	first, second := logger.Records[0], logger.Records[1]
+	expectEquals(t, first.Attempt, 1)
+	expectEquals(t, first.ErrorKind, mono.ErrorKindTransport)
+	expectEquals(t, first.Error, "failed to make request: dial with [REDACTED] failed")
+	expectEquals(t, first.RequestBody, `{"webHookUrl":"[REDACTED]"}`)
+	expectEquals(t, first.RequestBytes, 47)
+	expectEquals(t, second.Attempt, 2)
+	expectEquals(t, second.ErrorKind, mono.ErrorKind(""))
+	expectEquals(t, second.StatusCode, http.StatusOK)
+	expectEquals(t, second.ResponseBody, `{}`)
+}
+
+func TestScrubJSON(t *testing.T) {
+	body := `{"clientId":"3MSaMMtczs","name":"Мазепа Іван","webHookUrl":"https://example.com/hook",` +
+		`"accounts":[{"id":"kKGVoZuHWzqVoZuH","balance":10000000,"creditLimit":10000000,` +
+		`"currencyCode":980,"maskedPan":["537541******1234"],"iban":"UA733220010000026201234567890"}]}`
+
+	expected := `{"accounts":[{"balance":null,"creditLimit":null,"currencyCode":980,` +
+		`"iban":"UA***********************7890","id":"kK**********oZuH","maskedPan":["[REDACTED]"]}],` +
+		`"clientId":"[REDACTED]","name":"[REDACTED]","webHookUrl":"[REDACTED]"}`
+
+	expectEquals(t, string(mono.ScrubJSON([]byte(body))), expected)
+	expectEquals(t, string(mono.ScrubJSON([]byte(`not json`))), mono.Redacted)
+	expectEquals(t, string(mono.ScrubJSON([]byte(`{"webHookUrl":""}`))), `{"webHookUrl":""}`)
+	expectTrue(t, !strings.Contains(string(mono.ScrubJSON([]byte(`[{"comment":"hi"}]`))), "hi"))
+}
+
+func TestMaskID(t *testing.T) {
+	expectEquals(t, mono.MaskID("kKGVoZuHWzqVoZuH"), "kK**********oZuH")
+	expectEquals(t, mono.MaskID("short"), "*****")
+}
@@ -0,0 +1,177 @@
+package mono
+
+// MCCCategory is the spending category the merchant category code belongs to.
+type MCCCategory string
+
+const (
+	// CategoryGroceries covers supermarkets and food stores.
+	CategoryGroceries MCCCategory = "Groceries"
+	// CategoryRestaurants covers restaurants, cafes, bars and fast food.
+	CategoryRestaurants MCCCategory = "Restaurants"
+	// CategoryTransport covers public transport, taxi and tolls.
+	CategoryTransport MCCCategory = "Transport"
+	// CategoryFuel covers fuel stations.
+	CategoryFuel MCCCategory = "Fuel"
+	// CategoryTravel covers airlines, car rentals, hotels and travel agencies.
+	CategoryTravel MCCCategory = "Travel"
+	// CategoryHealth covers pharmacies, doctors and hospitals.
+	CategoryHealth MCCCategory = "Health"
+	// CategoryEntertainment covers digital goods, cinemas, and recreation.
+	CategoryEntertainment MCCCategory = "Entertainment"
+	// CategoryShopping covers retail stores not covered by other categories.
+	CategoryShopping MCCCategory = "Shopping"
+	// CategoryUtilities covers electricity, gas, water and other utilities.
+	CategoryUtilities MCCCategory = "Utilities"
+	// CategoryTelecom covers mobile, phone, internet and cable services.
+	CategoryTelecom MCCCategory = "Telecom"
+	// CategoryTransfers covers money transfers and top-ups.
+	CategoryTransfers MCCCategory = "Transfers"
+	// CategoryCash covers cash withdrawals.
+	CategoryCash MCCCategory = "Cash"
+	// CategoryServices covers personal, business and professional services.
+	CategoryServices MCCCategory = "Services"
+	// CategoryOther is for codes not covered by other categories.
+	CategoryOther MCCCategory = "Other"
+)
+
+// ClassifyMCC returns the spending category of the merchant category code.
+func ClassifyMCC(mcc int) MCCCategory {
+	if c := classifyExact(mcc); len(c) > 0 {
+		return c
+	}
+
+	switch {
+	case mcc >= 3000 && mcc <= 3999:
+		return CategoryTravel
+	case mcc >= 4000 && mcc <= 4799:
+		return CategoryTransport
+	case mcc >= 5000 && mcc <= 5999:
+		return CategoryShopping
+	case mcc >= 7000 && mcc <= 8999:
+		return CategoryServices
+	}
+
+	return CategoryOther
+}
+
+func classifyExact(mcc int) MCCCategory {
+	switch mcc {
+	case 5411, 5422, 5441, 5451, 5462, 5499:
+		return CategoryGroceries
+	case 5811, 5812, 5813, 5814:
+		return CategoryRestaurants
+	case 5541, 5542, 5983:
+		return CategoryFuel
+	case 4411, 4511, 4582, 4722, 7011, 7012, 7512, 7513:
+		return CategoryTravel
+	case 5122, 5912, 8011, 8021, 8031, 8041, 8042, 8043, 8049, 8050, 8062, 8071, 8099:
+		return CategoryHealth
+	case 5815, 5816, 5817, 5818, 7832, 7841, 7922, 7929, 7932, 7933, 7941, 7991, 7992, 7993, 7994, 7996, 7997, 7998, 7999:
+		return CategoryEntertainment
+	case 4900:
+		return CategoryUtilities
+	case 4812, 4813, 4814, 4815, 4816, 4821, 4899:
+		return CategoryTelecom
+	case 4829, 6012, 6051, 6536, 6537, 6538, 6540:
+		return CategoryTransfers
+	case 6010, 6011:
+		return CategoryCash
+	}
+
+	return ""
+}
+
+// MCCDescription returns the short English description of the merchant category code.
+// Codes without the known description return the description of their category.
+func MCCDescription(mcc int) string {
+	switch mcc {
+	case 4111:
+		return "Commuter Transport"
+	case 4121:
+		return "Taxicabs and Limousines"
+	case 4131:
+		return "Bus Lines"
+	case 4511:
+		return "Airlines"
+	case 4722:
+		return "Travel Agencies"
+	case 4784:
+		return "Tolls and Bridge Fees"
+	case 4812:
+		return "Telecommunication Equipment"
+	case 4814:
+		return "Telecommunication Services"
+	case 4816:
+		return "Computer Network Services"
+	case 4829:
+		return "Money Transfer"
+	case 4899:
+		return "Cable and Pay Television"
+	case 4900:
+		return "Utilities"
+	case 5311:
+		return "Department Stores"
+	case 5411:
+		return "Grocery Stores, Supermarkets"
+	case 5499:
+		return "Miscellaneous Food Stores"
+	case 5541:
+		return "Service Stations"
+	case 5542:
+		return "Automated Fuel Dispensers"
+	case 5651:
+		return "Family Clothing Stores"
+	case 5691:
+		return "Men's and Women's Clothing Stores"
+	case 5732:
+		return "Electronics Stores"
+	case 5812:
+		return "Eating Places, Restaurants"
+	case 5813:
+		return "Bars, Taverns, Nightclubs"
+	case 5814:
+		return "Fast Food Restaurants"
+	case 5815:
+		return "Digital Goods: Books, Movies, Music"
+	case 5816:
+		return "Digital Goods: Games"
+	case 5817:
+		return "Digital Goods: Applications"
+	case 5818:
+		return "Digital Goods: Large Merchant"
+	case 5912:
+		return "Drug Stores and Pharmacies"
+	case 5942:
+		return "Book Stores"
+	case 5977:
+		return "Cosmetic Stores"
+	case 5999:
+		return "Miscellaneous Retail Stores"
+	case 6010:
+		return "Manual Cash Disbursements"
+	case 6011:
+		return "Automated Cash Disbursements"
+	case 6012:
+		return "Financial Institutions"
+	case 6538:
+		return "Funding Transactions"
+	case 7011:
+		return "Hotels and Motels"
+	case 7230:
+		return "Beauty and Barber Shops"
+	case 7372:
+		return "Computer Programming Services"
+	case 7832:
+		return "Motion Picture Theaters"
+	case 7997:
+		return "Clubs, Country Clubs, Memberships"
+	case 8011:
+		return "Doctors"
+	case 8220:
+		return "Colleges, Universities"
+	case 8999:
+		return "Professional Services"
+	}
+
+	return string(ClassifyMCC(mcc))
+}
@@ -0,0 +1,24 @@
+package mono_test
+
+import (
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestClassifyMCC(t *testing.T) {
+	expectEquals(t, mono.ClassifyMCC(5411), mono.CategoryGroceries)
+	expectEquals(t, mono.ClassifyMCC(5814), mono.CategoryRestaurants)
+	expectEquals(t, mono.ClassifyMCC(4829), mono.CategoryTransfers)
+	expectEquals(t, mono.ClassifyMCC(3012), mono.CategoryTravel)
+	expectEquals(t, mono.ClassifyMCC(4111), mono.CategoryTransport)
+	expectEquals(t, mono.ClassifyMCC(5651), mono.CategoryShopping)
+	expectEquals(t, mono.ClassifyMCC(7230), mono.CategoryServices)
+	expectEquals(t, mono.ClassifyMCC(0), mono.CategoryOther)
+}
+
+func TestMCCDescription(t *testing.T) {
+	expectEquals(t, mono.MCCDescription(5411), "Grocery Stores, Supermarkets")
+	expectEquals(t, mono.MCCDescription(5462), "Groceries")
+	expectEquals(t, mono.MCCDescription(1), "Other")
+}
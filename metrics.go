@@ -0,0 +1,56 @@
+package mono
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookEvent is what happened to the incoming webhook.
+type WebhookEvent string
+
+const (
+	// WebhookReceived means the webhook arrived.
+	WebhookReceived WebhookEvent = "received"
+	// WebhookQueued means the webhook was parsed and queued for the channel.
+	WebhookQueued WebhookEvent = "queued"
+	// WebhookDropped means the webhook could not be parsed and was answered with the error.
+	WebhookDropped WebhookEvent = "dropped"
+)
+
+// Metrics receives measurements of the client.
+// The client reports requests, retries and webhooks itself.
+// Rate limiters and caches, like middlewares or PersonalPool, report waits and hits.
+type Metrics interface {
+	// RequestDone counts the request and its latency. Status is zero when there was no response.
+	RequestDone(endpoint string, status int, duration time.Duration)
+	// Retried counts the repeated request of the call.
+	Retried(endpoint string)
+	// RateLimitWaited counts the time the call waited for the rate limit.
+	RateLimitWaited(endpoint string, wait time.Duration)
+	// CacheHit counts the call answered from the cache.
+	CacheHit(endpoint string)
+	// Webhook counts the webhook event.
+	Webhook(event WebhookEvent)
+}
+
+// metricsMiddleware wraps the transport, so every attempt is measured.
+func (c tinyClient) metricsMiddleware(next Handler) Handler {
+	return func(ctx context.Context, call *Call) error {
+		started := time.Now()
+		err := next(ctx, call)
+
+		if call.Attempt > 1 {
+			c.metrics.Retried(call.Endpoint)
+		}
+
+		c.metrics.RequestDone(call.Endpoint, call.Response.StatusCode, time.Since(started))
+
+		return err
+	}
+}
+
+func (c tinyClient) webhookEvent(event WebhookEvent) {
+	if c.metrics != nil {
+		c.metrics.Webhook(event)
+	}
+}
@@ -0,0 +1,77 @@
+package mono_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+type metricstest struct {
+	Requests []string
+	Retries  []string
+	Webhooks []mono.WebhookEvent
+}
+
+func (m *metricstest) RequestDone(endpoint string, status int, _ time.Duration) {
+	m.Requests = append(m.Requests, endpoint+" "+http.StatusText(status))
+}
+
+func (m *metricstest) Retried(endpoint string) {
+	m.Retries = append(m.Retries, endpoint)
+}
+
+func (m *metricstest) RateLimitWaited(string, time.Duration) {}
+
+func (m *metricstest) CacheHit(string) {}
+
+func (m *metricstest) Webhook(event mono.WebhookEvent) {
+	m.Webhooks = append(m.Webhooks, event)
+}
+
+func TestWithMetrics_Requests(t *testing.T) {
+	client := &seqclienttest{
+		Resps: []*http.Response{nil, okResponse(currencyResponseBody)},
+		Errs:  []error{errors.New("boo")},
+	}
+	metrics := &metricstest{}
+
+	retry := func(next mono.Handler) mono.Handler {
+		return func(ctx context.Context, call *mono.Call) error {
+			if err := next(ctx, call); err == nil {
+				return nil
+			}
+
+			return next(ctx, call)
+		}
+	}
+
+	public := mono.NewPublic(mono.WithClient(client), mono.WithMetrics(metrics), mono.WithMiddleware(retry))
+
+	_, err := public.Currency(context.Background())
+	expectNoError(t, err)
+	expectDeepEquals(t, metrics.Requests, []string{"currency ", "currency OK"})
+	expectDeepEquals(t, metrics.Retries, []string{"currency"})
+}
+
+func TestWithMetrics_Webhooks(t *testing.T) {
+	metrics := &metricstest{}
+	personal := newPersonal(t, "api-token", mono.WithClient(&clienttest{}), mono.WithMetrics(metrics))
+
+	whChan, handlerFunc := personal.ListenForWebhooks(context.Background())
+
+	handlerFunc(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	handlerFunc(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(webhookBody))))
+	handlerFunc(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", &badReadCloser{}))
+
+	<-whChan
+
+	expectDeepEquals(t, metrics.Webhooks, []mono.WebhookEvent{
+		mono.WebhookReceived, mono.WebhookQueued, mono.WebhookReceived, mono.WebhookDropped,
+	})
+}
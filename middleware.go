@@ -0,0 +1,79 @@
+package mono
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Endpoints the calls are made to.
+const (
+	EndpointCurrency   = "currency"
+	EndpointClientInfo = "client-info"
+	EndpointStatement  = "statement"
+	EndpointWebhook    = "webhook"
+)
+
+// Call is the single API request passing through middlewares.
+type Call struct {
+	// Endpoint is the name of the API endpoint, one of Endpoint constants.
+	Endpoint string
+	// Account is the account the call is about, empty for calls about the client.
+	Account string
+	Method  string
+	URL     string
+	// Header holds headers to add to the request.
+	Header http.Header
+	// Payload is the request body before encoding, nil when there is no body.
+	Payload interface{}
+	// Result is the pointer the response is decoded into.
+	// Once the next handler returns without the error, it holds the decoded value.
+	Result interface{}
+	// Attempt counts requests made for the call, so middlewares that retry can tell them apart.
+	Attempt int
+	// Response is what the bank answered to the latest request of the call.
+	Response Response
+	// NoCache asks middlewares not to answer the call from the cache.
+	NoCache bool
+
+	requestBody   []byte
+	responseBytes int64
+	token         string
+	timeout       time.Duration
+	capture       *Response
+	// stream reads the successful response instead of decoding it into Result.
+	// It returns `*Error` when the body is broken and the error of the caller as is.
+	stream func(r io.Reader) error
+}
+
+// Response is the raw answer of the bank.
+type Response struct {
+	// StatusCode is zero when there was no response.
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Latency is the time from sending the request to reading the whole body.
+	Latency time.Duration
+	// Attempts counts requests the call made. It is set once the call is done,
+	// and it is zero when the call was answered without the request, e.g. from the cache.
+	Attempts int
+	// Cached is true when the response came from the cache of PersonalPool, with zero Latency.
+	Cached bool
+}
+
+// Handler makes the call. Errors of the request itself are `*Error`.
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps the handler to run the code around the call.
+// It can change the call, skip the next handler and fill the result, or replace the error.
+type Middleware func(next Handler) Handler
+
+// chain wraps the handler with middlewares, so the first middleware runs first.
+func chain(h Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	return h
+}
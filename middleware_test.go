@@ -0,0 +1,116 @@
+package mono_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestWithMiddleware_Order(t *testing.T) {
+	client := &clienttest{Resp: okResponse(currencyResponseBody)}
+
+	var trace []string
+
+	record := func(name string) mono.Middleware {
+		return func(next mono.Handler) mono.Handler {
+			return func(ctx context.Context, call *mono.Call) error {
+				trace = append(trace, name+" before "+call.Endpoint)
+				call.Header.Set("X-"+name, "yes")
+
+				err := next(ctx, call)
+
+				currencies := *call.Result.(*[]mono.CurrencyInfo)
+				trace = append(trace, name+" after "+mono.CurrencyAlpha(currencies[0].CurrencyCodeAISO4217))
+
+				return err
+			}
+		}
+	}
+
+	public := mono.NewPublic(
+		mono.WithClient(client),
+		mono.WithMiddleware(record("outer")),
+		mono.WithMiddleware(record("inner")),
+	)
+
+	actual, err := public.Currency(context.Background())
+	expectNoError(t, err)
+	expectDeepEquals(t, actual, expectedCurrencyResponseBody)
+	expectDeepEquals(t, trace, []string{
+		"outer before currency", "inner before currency", "inner after USD", "outer after USD",
+	})
+	expectEquals(t, client.Req.Header.Get("X-outer"), "yes")
+	expectEquals(t, client.Req.Header.Get("X-inner"), "yes")
+}
+
+func TestWithMiddleware_ShortCircuit(t *testing.T) {
+	client := &clienttest{Err: errors.New("must not be called")}
+
+	cached := func(next mono.Handler) mono.Handler {
+		return func(ctx context.Context, call *mono.Call) error {
+			if call.Endpoint != mono.EndpointClientInfo {
+				return next(ctx, call)
+			}
+
+			*call.Result.(*mono.UserInfo) = mono.UserInfo{Name: "cached"}
+
+			return nil
+		}
+	}
+
+	personal := newPersonal(t, "token", mono.WithClient(client), mono.WithMiddleware(cached))
+
+	info, err := personal.ClientInfo(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, info.Name, "cached")
+
+	_, err = personal.Webhook(context.Background())
+	expectNoError(t, err)
+
+	err = personal.ClearWebhook(context.Background())
+	expectError(t, err, "failed to make request: must not be called")
+}
+
+func TestWithMiddleware_TypedError(t *testing.T) {
+	client := &clienttest{Resp: &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"errorDescription": "Too many requests"}`))),
+	}}
+
+	var seen *mono.Error
+
+	inspect := func(next mono.Handler) mono.Handler {
+		return func(ctx context.Context, call *mono.Call) error {
+			err := next(ctx, call)
+			errors.As(err, &seen)
+
+			return err
+		}
+	}
+
+	personal := newPersonal(t, "token", mono.WithClient(client), mono.WithMiddleware(inspect))
+
+	_, err := personal.Statements(context.Background(), "0", time.Now().Add(-time.Hour), time.Now())
+	expectError(t, err, "mono error: Too many requests")
+	expectDeepEquals(t, seen, &mono.Error{
+		Kind:        mono.ErrorKindAPI,
+		StatusCode:  http.StatusTooManyRequests,
+		Description: "Too many requests",
+	})
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("boo")
+	err := &mono.Error{Kind: mono.ErrorKindTransport, Err: cause}
+
+	expectError(t, err, "failed to make request: boo")
+	expectTrue(t, errors.Is(err, cause))
+	expectError(t, &mono.Error{Kind: mono.ErrorKindClose, Err: cause}, "failed to close the body: boo")
+	expectError(t, &mono.Error{Kind: "retry"}, "failed to retry")
+}
@@ -0,0 +1,157 @@
+package monosync
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// FileStore keeps each account in its own JSON file in the directory.
+// Files are replaced atomically, so the interrupted write does not corrupt the store.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type accountFile struct {
+	Cursor Cursor               `json:"cursor"`
+	Items  []mono.StatementItem `json:"items"`
+}
+
+// OpenFileStore opens the store in the directory, creating it when it does not exist.
+func OpenFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create store: %v", err)
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+// Cursor returns the progress of the account, zero when it has never been synced.
+func (s *FileStore) Cursor(_ context.Context, account string) (Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read(account)
+
+	return f.Cursor, err
+}
+
+// Apply saves the change of the account.
+func (s *FileStore) Apply(_ context.Context, account string, change Change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read(account)
+	if err != nil {
+		return err
+	}
+
+	remove := make(map[string]bool, len(change.Remove)+len(change.Upsert))
+	for _, id := range change.Remove {
+		remove[id] = true
+	}
+
+	for _, item := range change.Upsert {
+		remove[item.ID] = true
+	}
+
+	items := make([]mono.StatementItem, 0, len(f.Items)+len(change.Upsert))
+
+	for _, item := range f.Items {
+		if !remove[item.ID] {
+			items = append(items, item)
+		}
+	}
+
+	items = append(items, change.Upsert...)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Time > items[j].Time
+	})
+
+	return s.write(account, accountFile{Cursor: change.Cursor, Items: items})
+}
+
+// Items returns the account transactions within the period, the latest first.
+func (s *FileStore) Items(_ context.Context, account string, from, to time.Time) ([]mono.StatementItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read(account)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []mono.StatementItem
+
+	for _, item := range f.Items {
+		if at := item.Time.Time(); !at.Before(from) && !at.After(to) {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func (s *FileStore) read(account string) (accountFile, error) {
+	var f accountFile
+
+	bts, err := ioutil.ReadFile(s.path(account))
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+
+	if err != nil {
+		return f, fmt.Errorf("failed to read store: %v", err)
+	}
+
+	if err := json.Unmarshal(bts, &f); err != nil {
+		return f, fmt.Errorf("failed to unmarshal store: %v", err)
+	}
+
+	return f, nil
+}
+
+func (s *FileStore) write(account string, f accountFile) error {
+	bts, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal store: %v", err)
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write store: %v", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(bts); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write store: %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write store: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(account)); err != nil {
+		return fmt.Errorf("failed to write store: %v", err)
+	}
+
+	return nil
+}
+
+// path names the file after the account ID encoded to be safe for file systems.
+func (s *FileStore) path(account string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(account))+".json")
+}
@@ -0,0 +1,87 @@
+package monosync_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monosync"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, cleanup := openStore(t)
+	defer cleanup()
+
+	cursor, err := store.Cursor(ctx, "a/b")
+	if err != nil || !cursor.Mark.IsZero() {
+		t.Fatalf("Unexpected cursor of the new account: %+v, %v", cursor, err)
+	}
+
+	mark := time.Unix(300, 0)
+	change := monosync.Change{
+		Cursor: monosync.Cursor{Mark: mark, Pending: time.Unix(100, 0)},
+		Upsert: []mono.StatementItem{{ID: "1", Time: 100, Hold: true}, {ID: "2", Time: 200}, {ID: "3", Time: 150}},
+	}
+
+	if err := store.Apply(ctx, "a/b", change); err != nil {
+		t.Fatal(err)
+	}
+
+	change = monosync.Change{
+		Cursor: monosync.Cursor{Mark: mark},
+		Upsert: []mono.StatementItem{{ID: "1", Time: 100}},
+		Remove: []string{"3"},
+	}
+
+	if err := store.Apply(ctx, "a/b", change); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := store.Items(ctx, "a/b", time.Unix(100, 0), time.Unix(200, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 || items[0].ID != "2" || items[1].ID != "1" || items[1].Hold {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+
+	if items, _ = store.Items(ctx, "a/b", time.Unix(101, 0), time.Unix(199, 0)); len(items) != 0 {
+		t.Fatalf("Expected no items within the period, got %+v", items)
+	}
+
+	if cursor, _ = store.Cursor(ctx, "a/b"); !cursor.Mark.Equal(mark) || !cursor.Pending.IsZero() {
+		t.Fatalf("Unexpected cursor: %+v", cursor)
+	}
+}
+
+func TestFileStore_Corrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monosync")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	store, err := monosync.OpenFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// YQ is the account "a" encoded.
+	if err := ioutil.WriteFile(filepath.Join(dir, "YQ.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.Cursor(context.Background(), "a")
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to unmarshal store") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
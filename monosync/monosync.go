@@ -0,0 +1,257 @@
+// Package monosync keeps the local copy of account transactions up to date.
+//
+// The syncer remembers the high-water mark of each account and asks the bank only for the newer windows,
+// staying within the rate limit. Holds are fetched again until they settle, and removed when they are reversed:
+//
+//	store, err := monosync.OpenFileStore("statements")
+//	if err != nil {
+//	  return err
+//	}
+//
+//	syncer := monosync.New(personal, store)
+//	if _, err := syncer.Sync(ctx); err != nil {
+//	  return err
+//	}
+//
+//	items, err := store.Items(ctx, account, from, to)
+package monosync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/internal/paging"
+	"github.com/kudrykv/go-monobank-api/internal/timer"
+)
+
+// Cursor is the sync progress of the account.
+type Cursor struct {
+	// Mark is the moment up to which transactions were fetched.
+	Mark time.Time `json:"mark"`
+	// Pending is the time of the oldest hold that has not settled yet, zero when there are none.
+	Pending time.Time `json:"pending,omitempty"`
+}
+
+// Change is the outcome of the sync to apply to the store at once.
+type Change struct {
+	Cursor Cursor
+	// Upsert are the items to add or to replace by ID.
+	Upsert []mono.StatementItem
+	// Remove are IDs of reversed holds.
+	Remove []string
+}
+
+// Store keeps transactions and the sync progress of accounts.
+type Store interface {
+	// Cursor returns the progress of the account, zero when it has never been synced.
+	Cursor(ctx context.Context, account string) (Cursor, error)
+	// Apply saves the change of the account.
+	Apply(ctx context.Context, account string, change Change) error
+	// Items returns the account transactions within the period, the latest first.
+	Items(ctx context.Context, account string, from, to time.Time) ([]mono.StatementItem, error)
+}
+
+// Result counts what the sync changed.
+type Result struct {
+	Added   int
+	Updated int
+	Removed int
+	Calls   int
+}
+
+// Option allows to change default values for the syncer.
+type Option func(*Syncer)
+
+// WithStart sets the moment to fetch transactions from for accounts that have never been synced.
+// Default is the longest period the bank returns at once before the first sync.
+func WithStart(start time.Time) Option {
+	return func(s *Syncer) {
+		s.start = start
+	}
+}
+
+// WithHoldWindow sets how long holds are fetched again while waiting for them to settle. Default is 31 days.
+func WithHoldWindow(d time.Duration) Option {
+	return func(s *Syncer) {
+		s.holdWindow = d
+	}
+}
+
+// WithClock allows to replace `time.Now` and waiting for the rate limit with the controlled clock.
+func WithClock(now func() time.Time, wait func(context.Context, time.Duration) error) Option {
+	return func(s *Syncer) {
+		s.now = now
+		s.wait = wait
+	}
+}
+
+// Syncer fetches new transactions into the store.
+// It is safe for concurrent use, and the calls wait for each other to keep within the rate limit.
+type Syncer struct {
+	personal   mono.Personal
+	store      Store
+	start      time.Time
+	holdWindow time.Duration
+	now        func() time.Time
+	wait       func(context.Context, time.Duration) error
+
+	mu       sync.Mutex
+	lastCall time.Time
+}
+
+// New creates the syncer of the personal client transactions.
+func New(personal mono.Personal, store Store, opts ...Option) *Syncer {
+	s := &Syncer{
+		personal:   personal,
+		store:      store,
+		holdWindow: 31 * 24 * time.Hour,
+		now:        time.Now,
+		wait:       timer.Sleep,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Sync fetches new transactions of the accounts. If there are no accounts, it syncs all accounts of the client.
+func (s *Syncer) Sync(ctx context.Context, accounts ...string) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(accounts) == 0 {
+		info, err := s.personal.ClientInfo(ctx)
+		if err != nil {
+			return Result{}, err
+		}
+
+		for _, account := range info.Accounts {
+			accounts = append(accounts, account.ID)
+		}
+	}
+
+	var total Result
+
+	for _, account := range accounts {
+		result, err := s.sync(ctx, account)
+
+		total.Added += result.Added
+		total.Updated += result.Updated
+		total.Removed += result.Removed
+		total.Calls += result.Calls
+
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+func (s *Syncer) sync(ctx context.Context, account string) (Result, error) {
+	var result Result
+
+	cursor, err := s.store.Cursor(ctx, account)
+	if err != nil {
+		return result, err
+	}
+
+	now := s.now()
+	from := cursor.Mark
+
+	if from.IsZero() {
+		from = s.start
+		if from.IsZero() {
+			from = now.Add(-mono.MaxAllowedDuration * time.Second)
+		}
+	}
+
+	if !cursor.Pending.IsZero() && cursor.Pending.Before(from) && now.Sub(cursor.Pending) < s.holdWindow {
+		from = cursor.Pending
+	}
+
+	fetched, err := s.fetch(ctx, account, from, now, &result)
+	if err != nil {
+		return result, err
+	}
+
+	stored, err := s.store.Items(ctx, account, from, now)
+	if err != nil {
+		return result, err
+	}
+
+	change := Change{Cursor: Cursor{Mark: now}}
+	known := make(map[string]mono.StatementItem, len(stored))
+
+	for _, item := range stored {
+		known[item.ID] = item
+	}
+
+	seen := make(map[string]bool, len(fetched))
+
+	for _, item := range fetched {
+		if seen[item.ID] {
+			continue
+		}
+
+		seen[item.ID] = true
+		old, ok := known[item.ID]
+
+		switch {
+		case !ok:
+			result.Added++
+		case old != item:
+			result.Updated++
+		default:
+			continue
+		}
+
+		change.Upsert = append(change.Upsert, item)
+	}
+
+	for _, item := range fetched {
+		if item.Hold && now.Sub(item.Time.Time()) < s.holdWindow {
+			change.Cursor.Pending = item.Time.Time()
+		}
+	}
+
+	for _, item := range stored {
+		if item.Hold && !seen[item.ID] {
+			change.Remove = append(change.Remove, item.ID)
+			result.Removed++
+		}
+	}
+
+	return result, s.store.Apply(ctx, account, change)
+}
+
+// fetch gets transactions of the period, the latest first.
+func (s *Syncer) fetch(
+	ctx context.Context, account string, from, to time.Time, result *Result,
+) ([]mono.StatementItem, error) {
+	return paging.Fetch(ctx, from, to, func(ctx context.Context, from, to time.Time) ([]mono.StatementItem, error) {
+		return s.statements(ctx, account, from, to, result)
+	})
+}
+
+// statements calls the bank once the rate limit allows.
+func (s *Syncer) statements(
+	ctx context.Context, account string, from, to time.Time, result *Result,
+) ([]mono.StatementItem, error) {
+	if !s.lastCall.IsZero() {
+		if left := mono.PersonalRateLimit - s.now().Sub(s.lastCall); left > 0 {
+			if err := s.wait(ctx, left); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.lastCall = s.now()
+	result.Calls++
+
+	return s.personal.Statements(ctx, account, from, to)
+}
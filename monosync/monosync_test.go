@@ -0,0 +1,241 @@
+package monosync_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monosync"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) wait(_ context.Context, d time.Duration) error {
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func TestSyncer_Sync(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "acc"}}})
+
+	for i := 0; i < 1200; i++ {
+		at := clk.Now().Add(-time.Duration(i) * 10 * time.Minute)
+		item := mono.StatementItem{ID: strconv.Itoa(i), Time: mono.Time(at.Unix()), Amount: -100, Hold: i == 5 || i == 7}
+
+		if err := srv.AddStatements("token", "acc", item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, cleanup := openStore(t)
+	defer cleanup()
+
+	syncer := monosync.New(newPersonal(t, srv), store,
+		monosync.WithStart(clk.Now().AddDate(0, 0, -40)),
+		monosync.WithClock(clk.Now, clk.wait),
+	)
+
+	synced := clk.Now()
+
+	result, err := syncer.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != (monosync.Result{Added: 1200, Calls: 4}) {
+		t.Fatalf("Unexpected first sync: %+v", result)
+	}
+
+	cursor, _ := store.Cursor(ctx, "acc")
+	if !cursor.Mark.Equal(synced) || !cursor.Pending.Equal(time.Unix(1577836800-7*600, 0)) {
+		t.Fatalf("Unexpected cursor: %+v", cursor)
+	}
+
+	clk.now = clk.now.Add(time.Hour)
+
+	settled := mono.StatementItem{ID: "5", Time: mono.Time(1577836800 - 5*600), Amount: -110}
+	if err := srv.UpdateStatement("token", "acc", settled); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.RemoveStatement("token", "acc", "7"); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := mono.StatementItem{ID: "new", Time: mono.Time(clk.Now().Add(-time.Minute).Unix()), Amount: -1}
+	if err := srv.AddStatements("token", "acc", fresh); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = syncer.Sync(ctx, "acc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != (monosync.Result{Added: 1, Updated: 1, Removed: 1, Calls: 1}) {
+		t.Fatalf("Unexpected second sync: %+v", result)
+	}
+
+	items, err := store.Items(ctx, "acc", time.Unix(0, 0), clk.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1200 || items[0] != fresh || items[6] != settled {
+		t.Fatalf("Unexpected items: %d %+v %+v", len(items), items[0], items[6])
+	}
+
+	if cursor, _ = store.Cursor(ctx, "acc"); !cursor.Pending.IsZero() {
+		t.Fatalf("Expected no pending holds, got %s", cursor.Pending)
+	}
+}
+
+func TestSyncer_Sync_PageBoundaryInSameSecond(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "acc"}}})
+
+	shared := clk.Now().Add(-490 * time.Minute)
+
+	for i := 0; i < 510; i++ {
+		at := clk.Now().Add(-time.Duration(i) * time.Minute)
+		if i >= 490 {
+			at = shared
+		}
+
+		item := mono.StatementItem{ID: strconv.Itoa(i), Time: mono.Time(at.Unix()), Amount: -100, Hold: i == 505}
+		if err := srv.AddStatements("token", "acc", item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, cleanup := openStore(t)
+	defer cleanup()
+
+	syncer := monosync.New(newPersonal(t, srv), store,
+		monosync.WithStart(clk.Now().AddDate(0, 0, -1)),
+		monosync.WithClock(clk.Now, clk.wait),
+	)
+
+	result, err := syncer.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != (monosync.Result{Added: 510, Calls: 2}) {
+		t.Fatalf("Unexpected first sync: %+v", result)
+	}
+
+	clk.now = clk.now.Add(time.Hour)
+
+	if result, err = syncer.Sync(ctx, "acc"); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Added != 0 || result.Updated != 0 || result.Removed != 0 {
+		t.Fatalf("Expected the hold to stay, got %+v", result)
+	}
+
+	if cursor, _ := store.Cursor(ctx, "acc"); !cursor.Pending.Equal(shared) {
+		t.Fatalf("Expected the pending hold at %s, got %+v", shared, cursor)
+	}
+}
+
+func TestSyncer_Sync_WaitsForRateLimit(t *testing.T) {
+	clk := &clock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "a"}, {ID: "b"}}})
+
+	store, cleanup := openStore(t)
+	defer cleanup()
+
+	start := clk.Now()
+	syncer := monosync.New(newPersonal(t, srv), store,
+		monosync.WithStart(clk.Now().Add(-time.Hour)),
+		monosync.WithClock(clk.Now, clk.wait),
+	)
+
+	if _, err := syncer.Sync(context.Background(), "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if waited := clk.Now().Sub(start); waited != mono.PersonalRateLimit {
+		t.Fatalf("Expected to wait once, waited %s", waited)
+	}
+}
+
+func TestSyncer_Sync_Fail(t *testing.T) {
+	clk := &clock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "a"}, {ID: "b"}}})
+
+	store, cleanup := openStore(t)
+	defer cleanup()
+
+	canceled := errors.New("canceled")
+	syncer := monosync.New(newPersonal(t, srv), store,
+		monosync.WithClock(clk.Now, func(context.Context, time.Duration) error { return canceled }),
+	)
+
+	result, err := syncer.Sync(context.Background(), "a", "b")
+	if err != canceled || result.Calls != 1 {
+		t.Fatalf("Unexpected result: %+v, %v", result, err)
+	}
+
+	if cursor, _ := store.Cursor(context.Background(), "b"); !cursor.Mark.IsZero() {
+		t.Fatalf("Expected the failed account to stay unsynced, got %+v", cursor)
+	}
+}
+
+func newPersonal(t *testing.T, srv *monotest.Server) mono.Personal {
+	personal, err := mono.NewPersonal("token", srv.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return personal
+}
+
+func openStore(t *testing.T) (*monosync.FileStore, func()) {
+	dir, err := ioutil.TempDir("", "monosync")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := monosync.OpenFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,272 @@
+package monotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Cassette is the list of recorded request and response pairs.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is the single recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request as it is stored in the cassette.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response as it is stored in the cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads the cassette from the file.
+func LoadCassette(path string) (*Cassette, error) {
+	bts, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %v", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(bts, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cassette: %v", err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to the file.
+func (c *Cassette) Save(path string) error {
+	bts, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, bts, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %v", err)
+	}
+
+	return nil
+}
+
+// RecordingClient passes requests to the wrapped client and records them into the cassette.
+//
+// `X-Token` and `X-Sign` headers are redacted in the cassette, account IDs in paths are masked with `mono.MaskID`,
+// and bodies are scrubbed with `mono.ScrubJSON`, the same way as in logs. The caller gets the original response.
+type RecordingClient struct {
+	client mono.HTTPClient
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecordingClient creates the client that records requests made through the given client.
+func NewRecordingClient(client mono.HTTPClient) *RecordingClient {
+	return &RecordingClient{client: client}
+}
+
+// Do makes the request with the wrapped client and records the pair.
+func (c *RecordingClient) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+
+	if req.Body != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close the body: %v", err)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	c.mu.Lock()
+	c.cassette.Interactions = append(c.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   redactPath(req.URL.Path),
+			Header: redactHeader(req.Header),
+			Body:   scrubBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       scrubBody(respBody),
+		},
+	})
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+// Cassette returns the copy of what was recorded so far.
+func (c *RecordingClient) Cassette() Cassette {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Cassette{Interactions: append([]Interaction(nil), c.cassette.Interactions...)}
+}
+
+// Save writes what was recorded so far to the file.
+func (c *RecordingClient) Save(path string) error {
+	cassette := c.Cassette()
+
+	return cassette.Save(path)
+}
+
+// ReplayingClient answers requests from the cassette without going to the network.
+//
+// Requests are matched by method, path and body. Each interaction is replayed once, in the recorded order.
+// A request without the matching interaction fails with the error.
+type ReplayingClient struct {
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewReplayingClient creates the client that replays the cassette.
+func NewReplayingClient(cassette Cassette) *ReplayingClient {
+	return &ReplayingClient{cassette: cassette, used: make([]bool, len(cassette.Interactions))}
+}
+
+// LoadReplayingClient creates the client that replays the cassette from the file.
+func LoadReplayingClient(path string) (*ReplayingClient, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplayingClient(*cassette), nil
+}
+
+// Do finds the recorded response for the request.
+func (c *ReplayingClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+
+	if req.Body != nil {
+		if err := req.Body.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close request body: %v", err)
+		}
+	}
+
+	redacted := scrubBody(body)
+	path := redactPath(req.URL.Path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, in := range c.cassette.Interactions {
+		if c.used[i] || in.Request.Method != req.Method || in.Request.Path != path || in.Request.Body != redacted {
+			continue
+		}
+
+		c.used[i] = true
+
+		return &http.Response{
+			Status:     http.StatusText(in.Response.StatusCode),
+			StatusCode: in.Response.StatusCode,
+			Header:     in.Response.Header.Clone(),
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, errors.New("monotest: no recorded interaction for " + req.Method + " " + req.URL.Path)
+}
+
+// Unused returns interactions that were not replayed yet.
+func (c *ReplayingClient) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unused []Interaction
+
+	for i, in := range c.cassette.Interactions {
+		if !c.used[i] {
+			unused = append(unused, in)
+		}
+	}
+
+	return unused
+}
+
+func readBody(body io.Reader) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	return ioutil.ReadAll(body)
+}
+
+func redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := header.Clone()
+
+	for _, key := range []string{"X-Token", "X-Sign"} {
+		if _, ok := redacted[key]; ok {
+			redacted.Set(key, mono.Redacted)
+		}
+	}
+
+	return redacted
+}
+
+// redactPath masks the account of `/personal/statement/{account}/{from}/{to}`.
+// The replayed request is masked the same way before matching.
+func redactPath(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) > 3 && parts[1] == "personal" && parts[2] == "statement" {
+		parts[3] = mono.MaskID(parts[3])
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// scrubBody keeps the empty body empty, so requests without one match on replay.
+func scrubBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	return string(mono.ScrubJSON(body))
+}
@@ -0,0 +1,190 @@
+package monotest_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+func TestRecordingClient_Replay(t *testing.T) {
+	srv := monotest.NewServer()
+	defer srv.Close()
+
+	srv.AddClient("secret-token", mono.UserInfo{
+		Name:     "John Doe",
+		Accounts: []mono.Account{{ID: "acc", Balance: 42, CurrencyCodeISO4217: 980}},
+	})
+	srv.SetCurrencies(mono.CurrencyInfo{CurrencyCodeAISO4217: 840, CurrencyCodeBISO4217: 980, RateBuy: 27.5})
+
+	ctx := context.Background()
+	recorder := monotest.NewRecordingClient(srv.Client())
+	personal := newPersonal(t, "secret-token", mono.WithDomain(srv.URL), mono.WithClient(recorder))
+	public := mono.NewPublic(mono.WithDomain(srv.URL), mono.WithClient(recorder))
+
+	info, err := personal.ClientInfo(ctx)
+	expectNoError(t, err)
+
+	if info.Name != "John Doe" {
+		t.Fatalf("Recording client must return the original response, got %s", info.Name)
+	}
+
+	_, err = public.Currency(ctx)
+	expectNoError(t, err)
+
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "cassette.json")
+	expectNoError(t, recorder.Save(path))
+
+	cassette, err := monotest.LoadCassette(path)
+	expectNoError(t, err)
+
+	if len(cassette.Interactions) != 2 {
+		t.Fatalf("Expected 2 interactions, got %d", len(cassette.Interactions))
+	}
+
+	recorded := cassette.Interactions[0]
+	if recorded.Request.Header.Get("X-Token") != mono.Redacted {
+		t.Fatalf("Token must be redacted, got %s", recorded.Request.Header.Get("X-Token"))
+	}
+
+	body := recorded.Response.Body
+	if strings.Contains(body, "John") || strings.Contains(body, "42") || !strings.Contains(body, `"currencyCode":980`) {
+		t.Fatalf("Only personal fields must be redacted, got %s", recorded.Response.Body)
+	}
+
+	replayer, err := monotest.LoadReplayingClient(path)
+	expectNoError(t, err)
+
+	public = mono.NewPublic(mono.WithDomain("https://offline"), mono.WithClient(replayer))
+	personal = newPersonal(t, "other-token", mono.WithDomain("https://offline"), mono.WithClient(replayer))
+
+	rates, err := public.Currency(ctx)
+	expectNoError(t, err)
+
+	if len(rates) != 1 || rates[0].RateBuy != 27.5 {
+		t.Fatalf("Unexpected rates: %v", rates)
+	}
+
+	info, err = personal.ClientInfo(ctx)
+	expectNoError(t, err)
+
+	if info.Name != mono.Redacted || info.Accounts[0].Balance != 0 || info.Accounts[0].CurrencyCodeISO4217 != 980 {
+		t.Fatalf("Unexpected client info: %v", info)
+	}
+
+	if len(replayer.Unused()) != 0 {
+		t.Fatal("Expected all interactions to be replayed")
+	}
+
+	_, err = personal.ClientInfo(ctx)
+	expectError(t, err, "failed to make request: monotest: no recorded interaction for GET /personal/client-info")
+}
+
+func TestReplayingClient_MatchesBody(t *testing.T) {
+	cassette := monotest.Cassette{Interactions: []monotest.Interaction{{
+		Request:  monotest.RecordedRequest{Method: "POST", Path: "/personal/webhook", Body: `{"webHookUrl":"[REDACTED]"}`},
+		Response: monotest.RecordedResponse{StatusCode: 200, Body: `{"status":"ok"}`},
+	}}}
+
+	ctx := context.Background()
+	replayer := monotest.NewReplayingClient(cassette)
+	personal := newPersonal(t, "token", mono.WithClient(replayer))
+
+	err := personal.ClearWebhook(ctx)
+	expectError(t, err, "failed to make request: monotest: no recorded interaction for POST /personal/webhook")
+
+	expectNoError(t, personal.SetWebhook(ctx, "https://b/wh"))
+}
+
+func TestRecordingClient_MasksAccountInPath(t *testing.T) {
+	srv := monotest.NewServer()
+	defer srv.Close()
+
+	account := "kKGVoZuHWzqVoZuH"
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: account}}})
+
+	ctx := context.Background()
+	recorder := monotest.NewRecordingClient(srv.Client())
+	personal := newPersonal(t, "token", mono.WithDomain(srv.URL), mono.WithClient(recorder))
+
+	from := time.Unix(1577836800, 0)
+	_, err := personal.Statements(ctx, account, from, from.Add(time.Hour))
+	expectNoError(t, err)
+
+	cassette := recorder.Cassette()
+	expected := "/personal/statement/kK**********oZuH/1577836800/1577840400"
+	if path := cassette.Interactions[0].Request.Path; path != expected {
+		t.Fatalf("Account must be masked in the path, got %s", path)
+	}
+
+	replayer := monotest.NewReplayingClient(cassette)
+	personal = newPersonal(t, "token", mono.WithDomain("https://offline"), mono.WithClient(replayer))
+
+	_, err = personal.Statements(ctx, account, from, from.Add(time.Hour))
+	expectNoError(t, err)
+}
+
+type brokenBody struct {
+	closed bool
+}
+
+func (b *brokenBody) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func (b *brokenBody) Close() error {
+	b.closed = true
+
+	return nil
+}
+
+type brokenClient struct {
+	body *brokenBody
+}
+
+func (c brokenClient) Do(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: c.body}, nil
+}
+
+func TestRecordingClient_ClosesBodyOnReadError(t *testing.T) {
+	body := &brokenBody{}
+	recorder := monotest.NewRecordingClient(brokenClient{body: body})
+	public := mono.NewPublic(mono.WithClient(recorder))
+
+	_, err := public.Currency(context.Background())
+	expectError(t, err, "failed to make request: failed to read response body: connection reset")
+
+	if !body.closed {
+		t.Fatal("Response body must be closed")
+	}
+}
+
+func TestLoadCassette_Fail(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	_, err := monotest.LoadCassette(filepath.Join(dir, "missing.json"))
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to read cassette: ") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "monotest")
+	expectNoError(t, err)
+
+	return dir, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
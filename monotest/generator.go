@@ -0,0 +1,309 @@
+package monotest
+
+import (
+	"encoding/base64"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+const (
+	currencyUAH = 980
+	currencyUSD = 840
+	currencyEUR = 978
+)
+
+// Generator produces realistic and deterministic client data.
+// Two generators with the same seed produce the same data given the same calls.
+//
+// Statements keep the running balance consistent: each item's `Balance` equals
+// the previous item's `Balance` plus its `Amount`.
+// Card purchases start as holds and settle after one to three days.
+// Purchases in USD and EUR use one rate per currency, picked on its first purchase
+// and kept for the generator's lifetime, which is the rate Currencies returns.
+type Generator struct {
+	rnd      *rand.Rand
+	settleAt map[string]time.Time
+	rates    map[int]mono.CurrencyInfo
+}
+
+type merchant struct {
+	mcc          int
+	weight       int
+	descriptions []string
+	min, max     int64
+}
+
+// NewGenerator creates the generator seeded with the value.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		rnd:      rand.New(rand.NewSource(seed)),
+		settleAt: map[string]time.Time{},
+		rates:    map[int]mono.CurrencyInfo{},
+	}
+}
+
+// UserInfo generates the client with the given number of accounts.
+// The first account is in UAH, the rest alternate between USD and EUR.
+func (g *Generator) UserInfo(accounts int) mono.UserInfo {
+	info := mono.UserInfo{
+		Name:     g.pick([]string{"Тарас Шевченко", "Леся Українка", "Іван Франко", "Ліна Костенко"}),
+		Accounts: make([]mono.Account, 0, accounts),
+	}
+
+	for i := 0; i < accounts; i++ {
+		currency := currencyUAH
+
+		switch {
+		case i == 0:
+		case i%2 == 1:
+			currency = currencyUSD
+		default:
+			currency = currencyEUR
+		}
+
+		info.Accounts = append(info.Accounts, g.Account(currency))
+	}
+
+	return info
+}
+
+// Account generates the account in the currency.
+func (g *Generator) Account(currency int) mono.Account {
+	acc := mono.Account{
+		ID:                  g.id(),
+		Balance:             g.between(100000, 5000000),
+		CurrencyCodeISO4217: currency,
+		CashbackType:        mono.CashbackNone,
+	}
+
+	if currency == currencyUAH {
+		acc.CreditLimit = g.between(0, 10) * 500000
+		acc.CashbackType = mono.Cashback(g.pick([]string{string(mono.CashbackUAH), string(mono.CashbackMiles)}))
+	}
+
+	return acc
+}
+
+// Statements generates n transactions for the account starting from the moment.
+// Items are ordered the same way the bank orders them -- the latest first.
+// The account balance is updated to the balance after the latest item.
+//
+// Purchases made less than their settlement period before the latest item are holds.
+func (g *Generator) Statements(account *mono.Account, from time.Time, n int) []mono.StatementItem {
+	items := make([]mono.StatementItem, 0, n)
+	at := from
+
+	for i := 0; i < n; i++ {
+		at = at.Add(time.Duration(g.between(10, 720)) * time.Minute)
+		item := g.item(account, at)
+		account.Balance = item.Balance
+		items = append(items, item)
+	}
+
+	items = g.Settle(items, at)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Time > items[j].Time
+	})
+
+	return items
+}
+
+// Settle returns the copy of items where holds, which settlement time has come by the moment, are settled.
+func (g *Generator) Settle(items []mono.StatementItem, now time.Time) []mono.StatementItem {
+	settled := make([]mono.StatementItem, len(items))
+
+	for i, item := range items {
+		if at, ok := g.settleAt[item.ID]; ok && !at.After(now) {
+			item.Hold = false
+		}
+
+		settled[i] = item
+	}
+
+	return settled
+}
+
+// Currencies returns the UAH rates of currencies that generated purchases were made in, ordered by the code.
+// Each rate is dated by the latest purchase in the currency, and its sell rate converts every purchase in it.
+func (g *Generator) Currencies() []mono.CurrencyInfo {
+	currencies := make([]mono.CurrencyInfo, 0, len(g.rates))
+	for _, rate := range g.rates {
+		currencies = append(currencies, rate)
+	}
+
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].CurrencyCodeAISO4217 < currencies[j].CurrencyCodeAISO4217
+	})
+
+	return currencies
+}
+
+// Populate registers the generated client with accounts on the server
+// and seeds each account with n transactions starting from the moment.
+// The server returns the rates of currencies the purchases were made in.
+func (g *Generator) Populate(srv *Server, token string, accounts, n int, from time.Time) (mono.UserInfo, error) {
+	info := g.UserInfo(accounts)
+	srv.AddClient(token, info)
+
+	for i := range info.Accounts {
+		items := g.Statements(&info.Accounts[i], from, n)
+		if err := srv.AddStatements(token, info.Accounts[i].ID, items...); err != nil {
+			return mono.UserInfo{}, err
+		}
+	}
+
+	srv.SetCurrencies(g.Currencies()...)
+
+	return info, nil
+}
+
+// WebhookData wraps the item the same way the bank does when it calls the webhook.
+func (g *Generator) WebhookData(account string, item mono.StatementItem) mono.WebhookData {
+	return mono.WebhookData{
+		Type: "StatementItem",
+		Data: mono.WebhookStatementItem{AccountID: account, StatementItem: item},
+	}
+}
+
+func (g *Generator) item(account *mono.Account, at time.Time) mono.StatementItem {
+	if account.Balance+account.CreditLimit < 50000 || g.rnd.Intn(25) == 0 {
+		return g.income(account, at)
+	}
+
+	m := g.merchant()
+	amount := -g.between(m.min, m.max)
+
+	item := mono.StatementItem{
+		ID:                  g.id(),
+		Time:                mono.Time(at.Unix()),
+		Description:         g.pick(m.descriptions),
+		MCC:                 m.mcc,
+		Amount:              amount,
+		OperationAmount:     amount,
+		CurrencyCodeISO4217: account.CurrencyCodeISO4217,
+	}
+
+	if m.mcc == 4829 {
+		item.CommissionRate = -amount / 200
+		item.Amount -= item.CommissionRate
+	} else {
+		item.Hold = true
+		g.settleAt[item.ID] = at.Add(time.Duration(g.between(1, 3)) * 24 * time.Hour)
+
+		if account.CashbackType != mono.CashbackNone {
+			item.CashbackAmount = -amount / 100
+		}
+
+		if account.CurrencyCodeISO4217 == currencyUAH && g.rnd.Intn(10) == 0 {
+			g.foreign(&item)
+		}
+	}
+
+	item.Balance = account.Balance + item.Amount
+
+	return item
+}
+
+func (g *Generator) income(account *mono.Account, at time.Time) mono.StatementItem {
+	amount := g.between(2000000, 6000000)
+
+	return mono.StatementItem{
+		ID:                  g.id(),
+		Time:                mono.Time(at.Unix()),
+		Description:         g.pick([]string{"Зарахування зарплати", "Від: Марія К.", "Поповнення з картки"}),
+		MCC:                 4829,
+		Amount:              amount,
+		OperationAmount:     amount,
+		CurrencyCodeISO4217: account.CurrencyCodeISO4217,
+		Balance:             account.Balance + amount,
+	}
+}
+
+// foreign converts the UAH purchase to the USD or EUR one at the rate of the currency,
+// keeping the account amount consistent with it.
+func (g *Generator) foreign(item *mono.StatementItem) {
+	item.CurrencyCodeISO4217 = currencyUSD
+	base := 27.0
+
+	if g.rnd.Intn(2) == 0 {
+		item.CurrencyCodeISO4217, base = currencyEUR, 30.0
+	}
+
+	info, ok := g.rates[item.CurrencyCodeISO4217]
+	if !ok {
+		rate := base + float64(g.between(0, 100))/100
+		info = mono.CurrencyInfo{
+			CurrencyCodeAISO4217: item.CurrencyCodeISO4217,
+			CurrencyCodeBISO4217: currencyUAH,
+			Date:                 item.Time,
+			RateBuy:              math.Round((rate-0.4)*100) / 100,
+			RateSell:             rate,
+		}
+	}
+
+	if info.Date < item.Time {
+		info.Date = item.Time
+	}
+
+	g.rates[item.CurrencyCodeISO4217] = info
+
+	item.OperationAmount = int64(math.Round(float64(item.Amount) / info.RateSell))
+	item.Amount = int64(math.Round(float64(item.OperationAmount) * info.RateSell))
+
+	if item.CashbackAmount > 0 {
+		item.CashbackAmount = -item.Amount / 100
+	}
+}
+
+func (g *Generator) merchant() merchant {
+	merchants := []merchant{
+		{5411, 30, []string{"Сільпо", "АТБ", "Novus", "Фора"}, 3000, 150000},
+		{5814, 12, []string{"McDonald’s", "Пузата Хата", "Lviv Croissants"}, 5000, 40000},
+		{5812, 8, []string{"Ресторан Канапа", "Pizza Celentano"}, 20000, 300000},
+		{5541, 8, []string{"OKKO", "WOG", "Shell"}, 50000, 200000},
+		{4111, 8, []string{"Київ Цифровий", "Uklon"}, 800, 25000},
+		{4829, 8, []string{"На картку Ольга П.", "Переказ на рахунок"}, 10000, 500000},
+		{5912, 6, []string{"Аптека Доброго Дня", "Аптека АНЦ"}, 5000, 80000},
+		{5651, 5, []string{"Zara", "H&M", "Reserved"}, 50000, 400000},
+		{4814, 5, []string{"Київстар", "lifecell", "Vodafone"}, 5000, 30000},
+		{4900, 4, []string{"Київенерго", "Київводоканал"}, 20000, 150000},
+		{5818, 6, []string{"Netflix", "Google Play", "Apple"}, 5000, 40000},
+	}
+
+	total := 0
+	for _, m := range merchants {
+		total += m.weight
+	}
+
+	n := g.rnd.Intn(total)
+
+	for _, m := range merchants {
+		if n < m.weight {
+			return m
+		}
+
+		n -= m.weight
+	}
+
+	return merchants[0]
+}
+
+func (g *Generator) id() string {
+	bts := make([]byte, 12)
+	g.rnd.Read(bts)
+
+	return base64.RawURLEncoding.EncodeToString(bts)
+}
+
+func (g *Generator) between(min, max int64) int64 {
+	return min + g.rnd.Int63n(max-min+1)
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rnd.Intn(len(values))]
+}
@@ -0,0 +1,179 @@
+package monotest_test
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+func TestGenerator_Deterministic(t *testing.T) {
+	from := time.Unix(1577836800, 0)
+
+	first := monotest.NewGenerator(42)
+	second := monotest.NewGenerator(42)
+
+	info := first.UserInfo(3)
+	if !reflect.DeepEqual(info, second.UserInfo(3)) {
+		t.Fatal("Expected the same user info for the same seed")
+	}
+
+	accFirst, accSecond := info.Accounts[0], info.Accounts[0]
+	if !reflect.DeepEqual(first.Statements(&accFirst, from, 100), second.Statements(&accSecond, from, 100)) {
+		t.Fatal("Expected the same statements for the same seed")
+	}
+
+	currencies := []int{info.Accounts[0].CurrencyCodeISO4217, info.Accounts[1].CurrencyCodeISO4217}
+	if currencies[0] != 980 || currencies[1] != 840 {
+		t.Fatalf("Unexpected currencies: %v", currencies)
+	}
+}
+
+func TestGenerator_Statements(t *testing.T) {
+	gen := monotest.NewGenerator(7)
+	account := gen.Account(980)
+	initial := account.Balance
+	from := time.Unix(1577836800, 0)
+
+	items := gen.Statements(&account, from, 3000)
+	if len(items) != 3000 {
+		t.Fatalf("Expected 3000 items, got %d", len(items))
+	}
+
+	if items[0].Balance != account.Balance {
+		t.Fatal("Expected account balance to follow the latest item")
+	}
+
+	if items[len(items)-1].Balance-items[len(items)-1].Amount != initial {
+		t.Fatal("Expected the earliest item to start from the initial balance")
+	}
+
+	mccs := map[int]int{}
+	holds, foreign := 0, 0
+
+	for i, item := range items {
+		mccs[item.MCC]++
+
+		if i+1 < len(items) {
+			prev := items[i+1]
+			if prev.Time >= item.Time || prev.Balance+item.Amount != item.Balance {
+				t.Fatalf("Inconsistent item %s after %s", item.ID, prev.ID)
+			}
+		}
+
+		if item.Hold {
+			holds++
+
+			if item.Time.Time().Before(items[0].Time.Time().Add(-3 * 24 * time.Hour)) {
+				t.Fatalf("Hold %s must have been settled", item.ID)
+			}
+		}
+
+		if item.CurrencyCodeISO4217 != 980 {
+			foreign++
+
+			rate := float64(item.Amount) / float64(item.OperationAmount)
+			if rate < 26.9 || rate > 31.1 {
+				t.Fatalf("Implausible rate %f for %s", rate, item.ID)
+			}
+		}
+	}
+
+	if holds == 0 || foreign == 0 || len(mccs) < 8 || mccs[5411] < mccs[4900] {
+		t.Fatalf("Implausible distribution: holds %d, foreign %d, mccs %v", holds, foreign, mccs)
+	}
+
+	settled := gen.Settle(items, items[0].Time.Time().Add(3*24*time.Hour))
+	for _, item := range settled {
+		if item.Hold {
+			t.Fatalf("Expected hold %s to settle", item.ID)
+		}
+	}
+
+	if !items[0].Hold && !items[1].Hold && !items[2].Hold {
+		t.Fatal("Expected the latest purchases to be still on hold")
+	}
+}
+
+func TestGenerator_Cashback(t *testing.T) {
+	gen := monotest.NewGenerator(3)
+	account := gen.Account(980)
+	account.CashbackType = mono.CashbackUAH
+
+	for _, item := range gen.Statements(&account, time.Unix(1577836800, 0), 200) {
+		if item.Hold && item.CashbackAmount != int64(math.Abs(float64(item.Amount/100))) {
+			t.Fatalf("Unexpected cashback %d for amount %d", item.CashbackAmount, item.Amount)
+		}
+	}
+}
+
+func TestGenerator_Populate(t *testing.T) {
+	clk := newClock()
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+	defer srv.Close()
+
+	gen := monotest.NewGenerator(1)
+
+	info, err := gen.Populate(srv, "token", 2, 50, clk.Now().Add(-10*24*time.Hour))
+	expectNoError(t, err)
+
+	personal := newPersonal(t, "token", srv.Options()...)
+
+	actual, err := personal.ClientInfo(context.Background())
+	expectNoError(t, err)
+
+	if !reflect.DeepEqual(*actual, info) {
+		t.Fatalf("Expected the server to hold the generated client")
+	}
+
+	rates, err := mono.NewPublic(srv.Options()...).Currency(context.Background())
+	expectNoError(t, err)
+
+	if !reflect.DeepEqual(rates, gen.Currencies()) {
+		t.Fatalf("Expected the server to hold the generated rates, got %v", rates)
+	}
+
+	// Generated items may run past the clock, so the period covers all of them.
+	from := clk.Now().Add(-10 * 24 * time.Hour)
+	items, err := personal.Statements(context.Background(), info.Accounts[0].ID, from, from.Add(30*24*time.Hour))
+	expectNoError(t, err)
+
+	latest := map[int]mono.StatementItem{}
+
+	for _, item := range items {
+		if _, ok := latest[item.CurrencyCodeISO4217]; !ok && item.CurrencyCodeISO4217 != 980 {
+			latest[item.CurrencyCodeISO4217] = item
+		}
+	}
+
+	if len(latest) == 0 || len(rates) != len(latest) {
+		t.Fatalf("Expected a rate for each of %d foreign currencies, got %v", len(latest), rates)
+	}
+
+	byCurrency := map[int]mono.CurrencyInfo{}
+
+	for _, rate := range rates {
+		if item := latest[rate.CurrencyCodeAISO4217]; rate.CurrencyCodeBISO4217 != 980 || rate.Date != item.Time {
+			t.Fatalf("Rate %v is not dated by the latest purchase %v", rate, item)
+		}
+
+		byCurrency[rate.CurrencyCodeAISO4217] = rate
+	}
+
+	// Every purchase in the currency converts at its one rate, not only the latest.
+	for _, item := range items {
+		rate, ok := byCurrency[item.CurrencyCodeISO4217]
+		if ok && math.Abs(float64(item.OperationAmount)*rate.RateSell-float64(item.Amount)) > 0.5 {
+			t.Fatalf("Rate %v does not match the purchase %v", rate, item)
+		}
+	}
+
+	data := gen.WebhookData(info.Accounts[0].ID, mono.StatementItem{ID: "tx"})
+	if data.Type != "StatementItem" || data.Data.AccountID != info.Accounts[0].ID {
+		t.Fatalf("Unexpected webhook data: %v", data)
+	}
+}
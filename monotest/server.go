@@ -0,0 +1,472 @@
+// Package monotest provides helpers for testing code built on top of the mono package.
+//
+// Server is the in-process fake of the Monobank API:
+//  srv := monotest.NewServer()
+//  defer srv.Close()
+//
+//  srv.AddClient("token", mono.UserInfo{Name: "John", Accounts: []mono.Account{{ID: "acc"}}})
+//  personal, err := mono.NewPersonal("token", srv.Options()...)
+package monotest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// Option allows to change default values for the server.
+type Option func(*Server)
+
+// WithClock allows to replace `time.Now` with the controlled clock.
+// The server uses it for rate limits and as the default `to` of the statement period.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithWebhookClient allows to change the client the server uses to call registered webhooks.
+// Use the client of `httptest.NewTLSServer` to reach webhooks served over test TLS.
+func WithWebhookClient(client mono.HTTPClient) Option {
+	return func(s *Server) {
+		s.webhookClient = client
+	}
+}
+
+// Server is the fake Monobank API backed by the in-memory bank state.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	now           func() time.Time
+	webhookClient mono.HTTPClient
+	currencies    []mono.CurrencyInfo
+	clients       map[string]*client
+	lastCalls     map[string]time.Time
+}
+
+type client struct {
+	info       mono.UserInfo
+	statements map[string][]mono.StatementItem
+}
+
+// NewServer starts the fake server.
+// The caller should call Close when finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		now:           time.Now,
+		webhookClient: &http.Client{},
+		clients:       map[string]*client{},
+		lastCalls:     map[string]time.Time{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bank/currency", s.currency)
+	mux.HandleFunc("/personal/client-info", s.clientInfo)
+	mux.HandleFunc("/personal/statement/", s.statement)
+	mux.HandleFunc("/personal/webhook", s.webhook)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Options returns client options pointing to the server.
+func (s *Server) Options() []mono.Option {
+	return []mono.Option{mono.WithDomain(s.URL), mono.WithClient(s.Client())}
+}
+
+// SetCurrencies replaces the list returned by `/bank/currency`.
+func (s *Server) SetCurrencies(currencies ...mono.CurrencyInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.currencies = append([]mono.CurrencyInfo(nil), currencies...)
+}
+
+// AddClient registers the client under the token.
+// Registering the token again replaces the client info and keeps the statements.
+func (s *Server) AddClient(token string, info mono.UserInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.clients[token]
+	if !ok {
+		c = &client{statements: map[string][]mono.StatementItem{}}
+		s.clients[token] = c
+	}
+
+	c.info = info
+	c.info.Accounts = append([]mono.Account(nil), info.Accounts...)
+}
+
+// AddStatements seeds the account with the transactions without calling the webhook.
+// The account balance is set to the balance of the latest transaction.
+func (s *Server) AddStatements(token, account string, items ...mono.StatementItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.addStatements(token, account, items)
+
+	return err
+}
+
+// UpdateStatement replaces the transaction with the same ID, like the bank does when the hold settles.
+// The account balance changes by the difference of the amounts, and the statement is sorted again by time.
+func (s *Server) UpdateStatement(token, account string, item mono.StatementItem) error {
+	return s.changeStatement(token, account, item.ID, &item)
+}
+
+// RemoveStatement removes the transaction, like the bank does when the hold is reversed.
+// The amount of the transaction is returned to the account balance.
+func (s *Server) RemoveStatement(token, account, id string) error {
+	return s.changeStatement(token, account, id, nil)
+}
+
+// Inject adds the transaction to the account and posts it to the registered webhook.
+// It returns the error if the webhook did not answer with 200 OK.
+// There is no call to the webhook if it is not set.
+func (s *Server) Inject(ctx context.Context, token, account string, item mono.StatementItem) error {
+	s.mu.Lock()
+
+	c, err := s.addStatements(token, account, []mono.StatementItem{item})
+	webhook := ""
+
+	if c != nil {
+		webhook = c.info.WebHookURL
+	}
+
+	s.mu.Unlock()
+
+	if err != nil || len(webhook) == 0 {
+		return err
+	}
+
+	data := mono.WebhookData{
+		Type: "StatementItem",
+		Data: mono.WebhookStatementItem{AccountID: account, StatementItem: item},
+	}
+
+	bts, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(bts))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	return s.callWebhook(req)
+}
+
+func (s *Server) addStatements(token, account string, items []mono.StatementItem) (*client, error) {
+	c, ok := s.clients[token]
+	if !ok {
+		return nil, errors.New("unknown token")
+	}
+
+	idx := accountIndex(c.info.Accounts, account)
+	if idx < 0 {
+		return nil, errors.New("unknown account " + account)
+	}
+
+	account = c.info.Accounts[idx].ID
+	statements := append(c.statements[account], items...)
+
+	sort.SliceStable(statements, func(i, j int) bool {
+		return statements[i].Time > statements[j].Time
+	})
+
+	c.statements[account] = statements
+
+	if len(statements) > 0 {
+		c.info.Accounts[idx].Balance = statements[0].Balance
+	}
+
+	return c, nil
+}
+
+func (s *Server) changeStatement(token, account, id string, item *mono.StatementItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.clients[token]
+	if !ok {
+		return errors.New("unknown token")
+	}
+
+	idx := accountIndex(c.info.Accounts, account)
+	if idx < 0 {
+		return errors.New("unknown account " + account)
+	}
+
+	statements := c.statements[c.info.Accounts[idx].ID]
+
+	for i := range statements {
+		if statements[i].ID != id {
+			continue
+		}
+
+		c.info.Accounts[idx].Balance -= statements[i].Amount
+
+		if item != nil {
+			statements[i] = *item
+			c.info.Accounts[idx].Balance += item.Amount
+
+			sort.SliceStable(statements, func(i, j int) bool {
+				return statements[i].Time > statements[j].Time
+			})
+		} else {
+			statements = append(statements[:i], statements[i+1:]...)
+		}
+
+		c.statements[c.info.Accounts[idx].ID] = statements
+
+		return nil
+	}
+
+	return errors.New("unknown statement " + id)
+}
+
+func (s *Server) callWebhook(req *http.Request) error {
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %v", err)
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		return fmt.Errorf("failed to close the body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("webhook answered with " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return nil
+}
+
+func (s *Server) currency(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The limit is keyed globally on purpose: the public endpoint needs no token,
+	// so the bank cannot tell callers apart and all clients of the server share it.
+	now := s.now()
+	if last, ok := s.lastCalls["currency"]; ok && now.Sub(last) < mono.CurrencyRateLimit {
+		writeError(w, http.StatusTooManyRequests, "Too many requests")
+		return
+	}
+
+	s.lastCalls["currency"] = now
+
+	writeJSON(w, append([]mono.CurrencyInfo{}, s.currencies...))
+}
+
+func (s *Server) clientInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.authorize(w, r, "client-info")
+	if !ok {
+		return
+	}
+
+	writeJSON(w, c.info)
+}
+
+func (s *Server) statement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/personal/statement/"), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	from, to, ok := s.period(w, parts[1:])
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.authorize(w, r, "statement")
+	if !ok {
+		return
+	}
+
+	idx := accountIndex(c.info.Accounts, parts[0])
+	if idx < 0 {
+		writeError(w, http.StatusBadRequest, "Account not found")
+		return
+	}
+
+	statements := make([]mono.StatementItem, 0)
+
+	for _, item := range c.statements[c.info.Accounts[idx].ID] {
+		if item.Time >= mono.Time(from) && item.Time <= mono.Time(to) && len(statements) < mono.MaxStatementItems {
+			statements = append(statements, item)
+		}
+	}
+
+	writeJSON(w, statements)
+}
+
+func (s *Server) period(w http.ResponseWriter, parts []string) (int64, int64, bool) {
+	from, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid 'from'")
+		return 0, 0, false
+	}
+
+	to := s.now().Unix()
+
+	if len(parts) == 2 {
+		if to, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid 'to'")
+			return 0, 0, false
+		}
+	}
+
+	if from > to {
+		writeError(w, http.StatusBadRequest, "'from' is greater than 'to'")
+		return 0, 0, false
+	}
+
+	if to-from > mono.MaxAllowedDuration {
+		writeError(w, http.StatusBadRequest, "Period must be no more than 31 days")
+		return 0, 0, false
+	}
+
+	return from, to, true
+}
+
+func (s *Server) webhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var body struct {
+		WebHookURL string `json:"webHookUrl"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid body")
+		return
+	}
+
+	s.mu.Lock()
+	_, ok := s.authorize(w, r, "")
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if len(body.WebHookURL) > 0 {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, body.WebHookURL, nil)
+		if err == nil {
+			err = s.callWebhook(req)
+		}
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Webhook verification failed")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.clients[r.Header.Get("X-Token")].info.WebHookURL = body.WebHookURL
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]string{"status": "ok"})
+}
+
+// authorize finds the client by the token and applies the rate limit of the endpoint.
+// Empty endpoint means the endpoint is not rate limited.
+// The caller must hold the lock.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, endpoint string) (*client, bool) {
+	token := r.Header.Get("X-Token")
+
+	c, ok := s.clients[token]
+	if !ok {
+		writeError(w, http.StatusForbidden, "Unknown 'X-Token'")
+		return nil, false
+	}
+
+	if len(endpoint) == 0 {
+		return c, true
+	}
+
+	key := token + " " + endpoint
+	now := s.now()
+
+	if last, ok := s.lastCalls[key]; ok && now.Sub(last) < mono.PersonalRateLimit {
+		writeError(w, http.StatusTooManyRequests, "Too many requests")
+		return nil, false
+	}
+
+	s.lastCalls[key] = now
+
+	return c, true
+}
+
+func accountIndex(accounts []mono.Account, account string) int {
+	if account == "0" && len(accounts) > 0 {
+		return 0
+	}
+
+	for i := range accounts {
+		if accounts[i].ID == account {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"errorDescription": description})
+}
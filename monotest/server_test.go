@@ -0,0 +1,295 @@
+package monotest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newClock() *clock {
+	return &clock{now: time.Unix(1577836800, 0)}
+}
+
+func expectError(t *testing.T, err error, expected string) {
+	t.Helper()
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Actual error is '%v', expected '%s'", err, expected)
+	}
+}
+
+func expectNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal("Got error: " + err.Error())
+	}
+}
+
+func TestServer_Currency(t *testing.T) {
+	clk := newClock()
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+	defer srv.Close()
+
+	rate := mono.CurrencyInfo{CurrencyCodeAISO4217: 840, CurrencyCodeBISO4217: 980, Date: 1577836800, RateBuy: 23.5}
+	srv.SetCurrencies(rate)
+
+	ctx := context.Background()
+	public := mono.NewPublic(srv.Options()...)
+
+	actual, err := public.Currency(ctx)
+	expectNoError(t, err)
+
+	if len(actual) != 1 || actual[0] != rate {
+		t.Fatalf("Unexpected currencies: %v", actual)
+	}
+
+	clk.Add(mono.CurrencyRateLimit - time.Second)
+
+	_, err = public.Currency(ctx)
+	expectError(t, err, "mono error: Too many requests")
+
+	clk.Add(time.Second)
+
+	_, err = public.Currency(ctx)
+	expectNoError(t, err)
+}
+
+func TestServer_ClientInfo(t *testing.T) {
+	clk := newClock()
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Name: "John", Accounts: []mono.Account{{ID: "acc", Balance: 100}}})
+
+	ctx := context.Background()
+
+	_, err := newPersonal(t, "unknown", srv.Options()...).ClientInfo(ctx)
+	expectError(t, err, "mono error: Unknown 'X-Token'")
+
+	personal := newPersonal(t, "token", srv.Options()...)
+
+	info, err := personal.ClientInfo(ctx)
+	expectNoError(t, err)
+
+	if info.Name != "John" || len(info.Accounts) != 1 || info.Accounts[0].Balance != 100 {
+		t.Fatalf("Unexpected client info: %v", info)
+	}
+
+	_, err = personal.ClientInfo(ctx)
+	expectError(t, err, "mono error: Too many requests")
+
+	clk.Add(mono.PersonalRateLimit)
+
+	_, err = personal.ClientInfo(ctx)
+	expectNoError(t, err)
+}
+
+func TestServer_Statements(t *testing.T) {
+	clk := newClock()
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "acc"}}})
+
+	items := make([]mono.StatementItem, 0, 600)
+	for i := 0; i < 600; i++ {
+		items = append(items, mono.StatementItem{
+			ID:      strconv.Itoa(i),
+			Time:    mono.Time(clk.Now().Add(-time.Duration(600-i) * time.Minute).Unix()),
+			Amount:  -100,
+			Balance: int64(-100 * (i + 1)),
+		})
+	}
+
+	expectNoError(t, srv.AddStatements("token", "acc", items...))
+	expectError(t, srv.AddStatements("token", "nope"), "unknown account nope")
+
+	ctx := context.Background()
+	personal := newPersonal(t, "token", srv.Options()...)
+
+	actual, err := personal.Statements(ctx, "acc", clk.Now().Add(-24*time.Hour), clk.Now())
+	expectNoError(t, err)
+
+	if len(actual) != mono.MaxStatementItems {
+		t.Fatalf("Expected %d items, got %d", mono.MaxStatementItems, len(actual))
+	}
+
+	if actual[0].ID != "599" || actual[len(actual)-1].ID != "100" {
+		t.Fatalf("Expected the latest items first, got %s..%s", actual[0].ID, actual[len(actual)-1].ID)
+	}
+
+	_, err = personal.Statements(ctx, "acc", clk.Now().Add(-time.Hour), clk.Now())
+	expectError(t, err, "mono error: Too many requests")
+
+	clk.Add(mono.PersonalRateLimit)
+
+	actual, err = personal.Statements(ctx, "0", clk.Now().Add(-11*time.Minute), clk.Now())
+	expectNoError(t, err)
+
+	if len(actual) != 10 {
+		t.Fatalf("Expected 10 items, got %d", len(actual))
+	}
+
+	info, err := personal.ClientInfo(ctx)
+	expectNoError(t, err)
+
+	if info.Accounts[0].Balance != -60000 {
+		t.Fatalf("Expected balance to follow the latest item, got %d", info.Accounts[0].Balance)
+	}
+}
+
+func TestServer_ChangeStatement(t *testing.T) {
+	clk := newClock()
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "acc"}}})
+
+	at := mono.Time(clk.Now().Add(-time.Hour).Unix())
+	expectNoError(t, srv.AddStatements("token", "acc",
+		mono.StatementItem{ID: "hold", Time: at, Amount: -100, Balance: 895, Hold: true},
+		mono.StatementItem{ID: "salary", Time: at - 30, Amount: 1000, Balance: 995},
+		mono.StatementItem{ID: "reversed", Time: at - 60, Amount: -5, Balance: -5, Hold: true},
+	))
+
+	settled := mono.StatementItem{ID: "hold", Time: at - 90, Amount: -110}
+	expectNoError(t, srv.UpdateStatement("token", "acc", settled))
+	expectNoError(t, srv.RemoveStatement("token", "0", "reversed"))
+	expectError(t, srv.RemoveStatement("token", "acc", "reversed"), "unknown statement reversed")
+	expectError(t, srv.UpdateStatement("nope", "acc", mono.StatementItem{}), "unknown token")
+	expectError(t, srv.RemoveStatement("token", "nope", "hold"), "unknown account nope")
+
+	personal := newPersonal(t, "token", srv.Options()...)
+
+	actual, err := personal.Statements(context.Background(), "acc", clk.Now().Add(-24*time.Hour), clk.Now())
+	expectNoError(t, err)
+
+	if len(actual) != 2 || actual[0].ID != "salary" || actual[1] != settled {
+		t.Fatalf("Unexpected statements: %+v", actual)
+	}
+
+	info, err := personal.ClientInfo(context.Background())
+	expectNoError(t, err)
+
+	if info.Accounts[0].Balance != 890 {
+		t.Fatalf("Expected balance to follow the changes, got %d", info.Accounts[0].Balance)
+	}
+}
+
+func TestServer_StatementsPeriod(t *testing.T) {
+	clk := newClock()
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "acc"}}})
+
+	to := clk.Now().Unix()
+	from := to - mono.MaxAllowedDuration - 1
+	path := "/personal/statement/acc/" + strconv.FormatInt(from, 10) + "/" + strconv.FormatInt(to, 10)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	expectNoError(t, err)
+	req.Header.Set("X-Token", "token")
+
+	resp, err := srv.Client().Do(req)
+	expectNoError(t, err)
+
+	var body struct {
+		ErrorDescription string `json:"errorDescription"`
+	}
+
+	expectNoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	expectNoError(t, resp.Body.Close())
+
+	if resp.StatusCode != http.StatusBadRequest || body.ErrorDescription != "Period must be no more than 31 days" {
+		t.Fatalf("Unexpected response: %d %s", resp.StatusCode, body.ErrorDescription)
+	}
+}
+
+func TestServer_Webhook(t *testing.T) {
+	received := make(chan mono.WebhookData, 1)
+	personal := newPersonal(t, "token")
+	whChan, handler := personal.ListenForWebhooks(context.Background())
+
+	wh := httptest.NewTLSServer(handler)
+	defer wh.Close()
+
+	go func() {
+		received <- <-whChan
+	}()
+
+	srv := monotest.NewServer(monotest.WithWebhookClient(wh.Client()))
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Accounts: []mono.Account{{ID: "acc"}}})
+
+	ctx := context.Background()
+	personal = newPersonal(t, "token", srv.Options()...)
+
+	err := personal.SetWebhook(ctx, "https://127.0.0.1:1/webhook")
+	expectError(t, err, "mono error: Webhook verification failed")
+
+	expectNoError(t, personal.SetWebhook(ctx, wh.URL+"/webhook"))
+
+	item := mono.StatementItem{ID: "tx", Time: 1577836800, Amount: -100, Balance: 900}
+	expectNoError(t, srv.Inject(ctx, "token", "acc", item))
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatal("died waiting on the webhook")
+
+	case data := <-received:
+		if data.Data.AccountID != "acc" || data.Data.StatementItem != item {
+			t.Fatalf("Unexpected webhook: %v", data)
+		}
+	}
+
+	actual, err := personal.Webhook(ctx)
+	expectNoError(t, err)
+
+	if !strings.HasSuffix(actual, "/webhook") {
+		t.Fatalf("Unexpected webhook URL: %s", actual)
+	}
+
+	expectNoError(t, personal.ClearWebhook(ctx))
+	expectNoError(t, srv.Inject(ctx, "token", "acc", mono.StatementItem{ID: "silent"}))
+	expectError(t, srv.Inject(ctx, "unknown", "acc", item), "unknown token")
+}
+
+func newPersonal(t *testing.T, token string, opts ...mono.Option) mono.Personal {
+	t.Helper()
+
+	personal, err := mono.NewPersonal(token, opts...)
+	expectNoError(t, err)
+
+	return personal
+}
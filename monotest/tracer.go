@@ -0,0 +1,109 @@
+package monotest
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// RecordedSpan is the span the Tracer has started.
+type RecordedSpan struct {
+	Name        string
+	SpanContext mono.SpanContext
+	// Parent is the context of the parent span, zero for root spans.
+	Parent     mono.SpanContext
+	Attributes map[string]string
+	Errors     []error
+	Ended      bool
+}
+
+// Tracer is the in-memory mono.Tracer that records spans for assertions.
+// IDs are sequential, so the spans are predictable. It is safe for concurrent use.
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+	next  uint64
+}
+
+// NewTracer creates the tracer without spans.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Start begins the span as the child of the span context in the context.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, mono.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.next++
+
+	span := &RecordedSpan{Name: name, Attributes: map[string]string{}}
+	span.SpanContext.Sampled = true
+	binary.BigEndian.PutUint64(span.SpanContext.SpanID[:], t.next)
+
+	if parent, ok := mono.SpanContextFromContext(ctx); ok && parent.IsValid() {
+		span.Parent = parent
+		span.SpanContext.TraceID = parent.TraceID
+		span.SpanContext.Sampled = parent.Sampled
+	} else {
+		binary.BigEndian.PutUint64(span.SpanContext.TraceID[8:], t.next)
+	}
+
+	t.spans = append(t.spans, span)
+
+	return mono.ContextWithSpanContext(ctx, span.SpanContext), &recordingSpan{tracer: t, span: span}
+}
+
+// Spans returns copies of started spans in the order they were started.
+func (t *Tracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]RecordedSpan, 0, len(t.spans))
+
+	for _, span := range t.spans {
+		s := *span
+		s.Attributes = make(map[string]string, len(span.Attributes))
+
+		for k, v := range span.Attributes {
+			s.Attributes[k] = v
+		}
+
+		s.Errors = append([]error(nil), span.Errors...)
+		spans = append(spans, s)
+	}
+
+	return spans
+}
+
+type recordingSpan struct {
+	tracer *Tracer
+	span   *RecordedSpan
+}
+
+func (s *recordingSpan) SpanContext() mono.SpanContext {
+	return s.span.SpanContext
+}
+
+func (s *recordingSpan) SetAttribute(key, value string) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.span.Attributes[key] = value
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.span.Errors = append(s.span.Errors, err)
+}
+
+func (s *recordingSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.span.Ended = true
+}
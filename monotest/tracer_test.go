@@ -0,0 +1,38 @@
+package monotest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+func TestTracer(t *testing.T) {
+	tracer := monotest.NewTracer()
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "child")
+
+	child.SetAttribute("key", "value")
+	child.RecordError(errors.New("boo"))
+	child.End()
+
+	spans := tracer.Spans()
+	if len(spans) != 2 || spans[0].Name != "root" || spans[1].Name != "child" {
+		t.Fatalf("Unexpected spans: %+v", spans)
+	}
+
+	if spans[0].Ended || !spans[1].Ended || spans[1].Attributes["key"] != "value" || len(spans[1].Errors) != 1 {
+		t.Fatalf("Unexpected span state: %+v", spans)
+	}
+
+	if spans[1].Parent != root.SpanContext() || spans[1].SpanContext.TraceID != root.SpanContext().TraceID {
+		t.Fatalf("Expected child to continue the root trace: %+v", spans)
+	}
+
+	if sc, ok := mono.SpanContextFromContext(ctx); !ok || sc != root.SpanContext() || !sc.IsValid() {
+		t.Fatalf("Expected context to carry the root span: %+v", sc)
+	}
+}
@@ -1,10 +1,19 @@
 package mono
 
+import "context"
+
 type optioner interface {
 	setDomain(string)
 	setClient(HTTPClient)
+	setMarshaller(Marshaller)
 	setUnmarshaller(Unmarshaller)
 	setWebhookBufferSize(uint32)
+	addMiddlewares([]Middleware)
+	setLogger(Logger, bool)
+	setMetrics(Metrics)
+	setTracer(Tracer)
+	setTokenRefresh(func(ctx context.Context) error)
+	setMaxResponseSize(int64)
 }
 
 // Option allows to change default values for client.
@@ -25,6 +34,13 @@ func WithClient(client HTTPClient) Option {
 	}
 }
 
+// WithMarshaller allows to change default `json.Marshal` for request bodies to something else.
+func WithMarshaller(m Marshaller) Option {
+	return func(o optioner) {
+		o.setMarshaller(m)
+	}
+}
+
 // WithUnmarshaller allows to change default `json.Unmarshal` to something else.
 func WithUnmarshaller(u Unmarshaller) Option {
 	return func(o optioner) {
@@ -39,3 +55,74 @@ func WithWebhookBufferSize(size uint32) Option {
 		o.setWebhookBufferSize(size)
 	}
 }
+
+// WithMiddleware adds middlewares around every API call of the client.
+// Middlewares run in the order they are added, the first one being the outermost.
+// The option can be used more than once.
+//  personal, err := mono.NewPersonal(token, mono.WithMiddleware(
+//    func(next mono.Handler) mono.Handler {
+//      return func(ctx context.Context, call *mono.Call) error {
+//        started := time.Now()
+//        err := next(ctx, call)
+//        log.Println(call.Endpoint, time.Since(started), err)
+//
+//        return err
+//      }
+//    },
+//  ))
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o optioner) {
+		o.addMiddlewares(middlewares)
+	}
+}
+
+// WithLogger logs every request the client makes, including each retry.
+// Records carry neither tokens nor bodies, and account IDs are masked.
+func WithLogger(logger Logger) Option {
+	return func(o optioner) {
+		o.setLogger(logger, false)
+	}
+}
+
+// WithBodyLogger logs every request like WithLogger, adding request and response bodies
+// with personal fields scrubbed by ScrubJSON.
+func WithBodyLogger(logger Logger) Option {
+	return func(o optioner) {
+		o.setLogger(logger, true)
+	}
+}
+
+// WithMetrics reports requests, retries and webhooks of the client to metrics.
+// See the promtext package for the Prometheus adapter.
+func WithMetrics(metrics Metrics) Option {
+	return func(o optioner) {
+		o.setMetrics(metrics)
+	}
+}
+
+// WithTracer opens the span around every API call and webhook handling.
+// Calls pass the `traceparent` header to the bank, and webhook spans continue the trace of the incoming request.
+// See monotest.Tracer for the in-memory tracer.
+func WithTracer(tracer Tracer) Option {
+	return func(o optioner) {
+		o.setTracer(tracer)
+	}
+}
+
+// WithTokenRefresh sets the callback for the rejected token.
+// Once the bank answers with 401 or 403, the client calls it and repeats the request
+// with the token the source gives next. The error of the callback keeps the original error.
+func WithTokenRefresh(refresh func(ctx context.Context) error) Option {
+	return func(o optioner) {
+		o.setTokenRefresh(refresh)
+	}
+}
+
+// WithMaxResponseSize limits the response body the client reads, guarding against huge or hostile bodies.
+// Larger bodies fail the call with ErrorKindRead. Zero or negative size removes the limit.
+// Default value is DefaultMaxResponseSize.
+func WithMaxResponseSize(size int64) Option {
+	return func(o optioner) {
+		o.setMaxResponseSize(size)
+	}
+}
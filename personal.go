@@ -1,15 +1,14 @@
 package mono
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -17,62 +16,184 @@ type personal struct {
 	core
 }
 
-// NewPersonal creates the client to access Personal API.
-func NewPersonal(apiToken string, opts ...Option) Personal {
+// NewPersonal creates the client to access Personal API with the fixed token.
+func NewPersonal(apiToken string, opts ...Option) (Personal, error) {
 	if len(apiToken) == 0 {
-		panic("api token is required")
+		return nil, errors.New("api token is required")
+	}
+
+	return NewPersonalWithTokenSource(StaticToken(apiToken), opts...)
+}
+
+// NewPersonalWithTokenSource creates the client to access Personal API
+// that asks the source for the token before every request.
+func NewPersonalWithTokenSource(tokens TokenSource, opts ...Option) (Personal, error) {
+	if tokens == nil {
+		return nil, errors.New("token source is required")
 	}
 
 	p := personal{core: newCore(opts...)}
-	p.token = apiToken
+	p.tokens = tokens
 
-	return p
+	return p, nil
 }
 
-func (p personal) ClientInfo(ctx context.Context) (*UserInfo, error) {
+func (p personal) ClientInfo(ctx context.Context, opts ...CallOption) (*UserInfo, error) {
+	url := p.domain + "/personal/client-info"
+
 	var userInfo UserInfo
-	if err := p.request(ctx, http.MethodGet, p.domain+"/personal/client-info", nil, &userInfo); err != nil {
+	if err := p.request(ctx, EndpointClientInfo, http.MethodGet, url, nil, &userInfo, opts...); err != nil {
 		return nil, err
 	}
 
 	return &userInfo, nil
 }
 
-func (p personal) LatestStatements(ctx context.Context, account string, from time.Time) ([]StatementItem, error) {
-	return p.Statements(ctx, account, from, time.Now())
+func (p personal) LatestStatements(
+	ctx context.Context, account string, from time.Time, opts ...CallOption,
+) ([]StatementItem, error) {
+	return p.Statements(ctx, account, from, time.Now(), opts...)
+}
+
+func (p personal) Statements(
+	ctx context.Context, account string, from, to time.Time, opts ...CallOption,
+) ([]StatementItem, error) {
+	url, err := p.statementURL(account, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []StatementItem
+
+	call := &Call{Endpoint: EndpointStatement, Account: account, Method: http.MethodGet, URL: url, Result: &statements}
+	if err := p.call(ctx, call, opts...); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+func (p personal) StreamStatements(
+	ctx context.Context, account string, from, to time.Time, fn func(StatementItem) error, opts ...CallOption,
+) error {
+	url, err := p.statementURL(account, from, to)
+	if err != nil {
+		return err
+	}
+
+	call := &Call{Endpoint: EndpointStatement, Account: account, Method: http.MethodGet, URL: url}
+	call.stream = func(r io.Reader) error {
+		return decodeStatements(r, fn)
+	}
+
+	return p.call(ctx, call, opts...)
 }
 
-func (p personal) Statements(ctx context.Context, account string, from, to time.Time) ([]StatementItem, error) {
+func (p personal) statementURL(account string, from, to time.Time) (string, error) {
 	if len(account) == 0 {
-		return nil, errors.New("account must be set")
+		return "", errors.New("account must be set")
 	}
 
 	if from.After(to) {
-		return nil, errors.New("`from` should be less than `to`")
+		return "", errors.New("`from` should be less than `to`")
 	}
 
 	if to.Unix()-from.Unix() > MaxAllowedDuration {
-		return nil, errors.New("max allowed duration is " + strconv.Itoa(MaxAllowedDuration) + " seconds")
+		return "", errors.New("max allowed duration is " + strconv.Itoa(MaxAllowedDuration) + " seconds")
 	}
 
 	fromUnix := strconv.FormatInt(from.Unix(), 10)
 	toUnix := strconv.FormatInt(to.Unix(), 10)
-	url := p.domain + "/personal/statement/" + account + "/" + fromUnix + "/" + toUnix
 
-	var statements []StatementItem
-	if err := p.request(ctx, http.MethodGet, url, nil, &statements); err != nil {
-		return nil, err
+	return p.domain + "/personal/statement/" + account + "/" + fromUnix + "/" + toUnix, nil
+}
+
+// decodeStatements decodes the JSON array item by item, so only one item is held at a time.
+func decodeStatements(r io.Reader, fn func(StatementItem) error) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '['); err != nil {
+		return err
 	}
 
-	return statements, nil
+	for dec.More() {
+		var item StatementItem
+		if err := dec.Decode(&item); err != nil {
+			return &Error{Kind: ErrorKindUnmarshal, StatusCode: http.StatusOK, Err: err}
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err == nil && token != delim {
+		err = fmt.Errorf("expected %v, got %v", delim, token)
+	}
+
+	if err != nil {
+		return &Error{Kind: ErrorKindUnmarshal, StatusCode: http.StatusOK, Err: err}
+	}
+
+	return nil
+}
+
+func (p personal) SetWebhook(ctx context.Context, webhook string, opts ...CallOption) error {
+	if len(webhook) == 0 {
+		return p.ClearWebhook(ctx, opts...)
+	}
+
+	if err := ValidateWebhookURL(webhook); err != nil {
+		return err
+	}
+
+	return p.setWebhook(ctx, webhook, opts)
+}
+
+func (p personal) Webhook(ctx context.Context, opts ...CallOption) (string, error) {
+	userInfo, err := p.ClientInfo(ctx, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return userInfo.WebHookURL, nil
+}
+
+func (p personal) ClearWebhook(ctx context.Context, opts ...CallOption) error {
+	return p.setWebhook(ctx, "", opts)
+}
+
+func (p personal) RotateWebhook(ctx context.Context, webhook string, opts ...CallOption) (string, error) {
+	if err := ValidateWebhookURL(webhook); err != nil {
+		return "", err
+	}
+
+	previous, err := p.Webhook(ctx, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.handshake(ctx, webhook); err != nil {
+		return "", err
+	}
+
+	if err := p.setWebhook(ctx, webhook, opts); err != nil {
+		return "", err
+	}
+
+	return previous, nil
 }
 
-func (p personal) SetWebhook(ctx context.Context, webhook string) error {
-	wh := strings.ReplaceAll(strings.ReplaceAll(webhook, `"`, `\"`), "\n", "\\n")
-	body := bytes.NewReader([]byte(`{"webHookUrl":"` + wh + `"}`))
+func (p personal) setWebhook(ctx context.Context, webhook string, opts []CallOption) error {
+	body := webhookRequest{WebHookURL: webhook}
 
 	var empty struct{}
-	return p.request(ctx, http.MethodPost, p.domain+"/personal/webhook", body, &empty)
+	return p.request(ctx, EndpointWebhook, http.MethodPost, p.domain+"/personal/webhook", body, &empty, opts...)
 }
 
 func (p personal) ParseWebhook(_ context.Context, rc io.ReadCloser) (*WebhookData, error) {
@@ -93,16 +214,40 @@ func (p personal) ParseWebhook(_ context.Context, rc io.ReadCloser) (*WebhookDat
 	return &wh, nil
 }
 
+// ListenForWebhooks returns the channel and the handler that receives webhooks, like `Personal.ListenForWebhooks`.
+// The handler never calls the API, so it needs no token. Options like WithWebhookBufferSize, WithUnmarshaller,
+// WithMetrics and WithTracer apply to it.
+func ListenForWebhooks(ctx context.Context, opts ...Option) (<-chan WebhookData, http.HandlerFunc) {
+	return personal{core: newCore(opts...)}.ListenForWebhooks(ctx)
+}
+
 func (p personal) ListenForWebhooks(_ context.Context) (<-chan WebhookData, http.HandlerFunc) {
 	whch := make(chan WebhookData, p.whBufferSize)
 
 	return whch, func(w http.ResponseWriter, r *http.Request) {
-		wh, err := p.ParseWebhook(r.Context(), r.Body)
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		p.webhookEvent(WebhookReceived)
+
+		ctx, span := p.startWebhookSpan(r)
+		defer span.End()
+
+		wh, err := p.ParseWebhook(ctx, r.Body)
 		if err != nil {
+			span.RecordError(err)
+			p.webhookEvent(WebhookDropped)
 			w.WriteHeader(http.StatusInternalServerError)
+
 			return
 		}
 
+		span.SetAttribute(AttributeAccount, HashAccount(wh.Data.AccountID))
+
+		p.webhookEvent(WebhookQueued)
+
 		go func() {
 			whch <- *wh
 		}()
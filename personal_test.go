@@ -3,6 +3,7 @@ package mono_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -19,6 +20,7 @@ var personalResponseBody = `{
   "accounts": [
     {
       "id": "kKGVoZuHWzqVoZuH",
+      "iban": "UA733220010000026201234567890",
       "balance": 10000000,
       "creditLimit": 10000000,
       "currencyCode": 980,
@@ -32,6 +34,7 @@ var expectedPersonal = mono.UserInfo{
 	WebHookURL: "https://url/leading/to/the/webhook",
 	Accounts: []mono.Account{{
 		ID:                  "kKGVoZuHWzqVoZuH",
+		IBAN:                "UA733220010000026201234567890",
 		Balance:             10000000,
 		CreditLimit:         10000000,
 		CurrencyCodeISO4217: 980,
@@ -40,20 +43,26 @@ var expectedPersonal = mono.UserInfo{
 }
 
 func TestNewPersonal(t *testing.T) {
-	p := mono.NewPersonal("token")
+	p, err := mono.NewPersonal("token")
+	expectNoError(t, err)
 	expectNotNil(t, p)
 }
 
-func TestNewPersonal_PanicOnEmptyToken(t *testing.T) {
-	defer func() {
-		err, ok := recover().(string)
+func TestNewPersonal_EmptyToken(t *testing.T) {
+	p, err := mono.NewPersonal("")
+	expectError(t, err, "api token is required")
+	expectTrue(t, p == nil)
+
+	p, err = mono.NewPersonalWithTokenSource(nil)
+	expectError(t, err, "token source is required")
+	expectTrue(t, p == nil)
 
-		expectTrue(t, ok)
-		expectNotNil(t, err)
-		expectError(t, errors.New(err), "api token is required")
-	}()
+	pool := mono.NewPersonalPool(func(context.Context, string) (string, error) { return "", nil })
 
-	mono.NewPersonal("")
+	p, err = pool.For(context.Background(), "user")
+	expectError(t, err, "api token is required")
+	expectTrue(t, p == nil)
+	expectEquals(t, pool.Len(), 0)
 }
 
 func TestPersonal_ClientInfo_Succ(t *testing.T) {
@@ -66,7 +75,7 @@ func TestPersonal_ClientInfo_Succ(t *testing.T) {
 	ctx := context.Background()
 	apiToken := "api-token"
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	actual, err := personal.ClientInfo(ctx)
 
@@ -84,7 +93,7 @@ func TestPersonal_ClientInfo_Fail(t *testing.T) {
 	ctx := context.Background()
 	apiToken := "api-token"
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	_, err := personal.ClientInfo(ctx)
 	expectError(t, err, "mono error: go away")
@@ -102,7 +111,9 @@ var statementsResponseBody = `[
     "currencyCode": 980,
     "commissionRate": 0,
     "cashbackAmount": 19000,
-    "balance": 10050000
+    "balance": 10050000,
+    "comment": "За каву",
+    "counterName": "Кав'ярня"
   }
 ]`
 
@@ -118,6 +129,8 @@ var expectedStatementsResponse = []mono.StatementItem{{
 	CommissionRate:      0,
 	CashbackAmount:      19000,
 	Balance:             10050000,
+	Comment:             "За каву",
+	CounterName:         "Кав'ярня",
 }}
 
 func TestPersonal_Statements_Succ(t *testing.T) {
@@ -133,7 +146,7 @@ func TestPersonal_Statements_Succ(t *testing.T) {
 	from := time.Now().Add(-time.Hour * 24 * 15) // 15 days
 	to := time.Now()
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	statements, err := personal.Statements(ctx, accountID, from, to)
 	expectNoError(t, err)
@@ -153,7 +166,7 @@ func TestPersonal_Statements_Fail(t *testing.T) {
 	from := time.Now().Add(-time.Hour * 24 * 15) // 15 days
 	to := time.Now()
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	_, err := personal.Statements(ctx, "", from, to)
 	expectError(t, err, "account must be set")
@@ -168,6 +181,79 @@ func TestPersonal_Statements_Fail(t *testing.T) {
 	expectError(t, err, "mono error: go away")
 }
 
+func TestPersonal_StreamStatements_Succ(t *testing.T) {
+	client := &clienttest{Resp: okResponse(statementsResponseBody)}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	var statements []mono.StatementItem
+
+	collect := func(item mono.StatementItem) error {
+		statements = append(statements, item)
+		return nil
+	}
+
+	from := time.Now().Add(-time.Hour)
+	err := personal.StreamStatements(context.Background(), "deadbeef", from, time.Now(), collect)
+	expectNoError(t, err)
+	expectDeepEquals(t, statements, expectedStatementsResponse)
+}
+
+func TestPersonal_StreamStatements_Stop(t *testing.T) {
+	body := `[{"id": "1"}, {"id": "2"}, {"id": "3"}]`
+	client := &clienttest{Resp: okResponse(body)}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+	stop := errors.New("stop")
+
+	var ids []string
+
+	err := personal.StreamStatements(context.Background(), "deadbeef", time.Now().Add(-time.Hour), time.Now(),
+		func(item mono.StatementItem) error {
+			ids = append(ids, item.ID)
+			if item.ID == "2" {
+				return stop
+			}
+
+			return nil
+		},
+	)
+
+	expectEquals(t, err, stop)
+	expectDeepEquals(t, ids, []string{"1", "2"})
+}
+
+func TestPersonal_StreamStatements_Fail(t *testing.T) {
+	ctx := context.Background()
+	from := time.Now().Add(-time.Hour)
+	to := time.Now()
+	skip := func(mono.StatementItem) error { return nil }
+
+	client := &clienttest{Resp: &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(failResponseBody))),
+	}}
+	personal := newPersonal(t, "api-token", mono.WithClient(client), mono.WithMaxResponseSize(64))
+
+	expectError(t, personal.StreamStatements(ctx, "", from, to, skip), "account must be set")
+	expectError(t, personal.StreamStatements(ctx, "deadbeef", from, to, skip), "mono error: go away")
+
+	client.Resp = okResponse(`{"id": "1"}`)
+	expectErrorStartsWith(t, personal.StreamStatements(ctx, "deadbeef", from, to, skip), "failed to unmarshal body: ")
+
+	client.Resp = okResponse(`[{"id": 1}]`)
+	expectErrorStartsWith(t, personal.StreamStatements(ctx, "deadbeef", from, to, skip), "failed to unmarshal body: ")
+
+	client.Resp = okResponse(`[{"id": "1"}, {"id": "2"}, {"id": "3"}, {"id": "4"}, {"id": "5"}, {"id": "6"}]`)
+
+	var seen int
+
+	err := personal.StreamStatements(ctx, "deadbeef", from, to, func(mono.StatementItem) error {
+		seen++
+		return nil
+	})
+	expectError(t, err, "failed to read body: body is larger than 64 bytes")
+	expectTrue(t, seen < 6)
+}
+
 func TestPersonal_LatestStatements(t *testing.T) {
 	client := &clienttest{}
 	client.Resp = &http.Response{
@@ -180,7 +266,7 @@ func TestPersonal_LatestStatements(t *testing.T) {
 	accountID := "deadbeef"
 	from := time.Now().Add(-time.Hour * 24 * 15) // 15 days
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	statements, err := personal.LatestStatements(ctx, accountID, from)
 	expectNoError(t, err)
@@ -198,7 +284,7 @@ func TestPersonal_SetWebhook_Succ(t *testing.T) {
 	apiToken := "api-token"
 	wh := "https://domain/webhook"
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	err := personal.SetWebhook(ctx, wh)
 	expectNoError(t, err)
@@ -215,12 +301,124 @@ func TestPersonal_SetWebhook_Fail(t *testing.T) {
 	apiToken := "api-token"
 	wh := "https://domain/webhook"
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	err := personal.SetWebhook(ctx, wh)
 	expectError(t, err, "mono error: go away")
 }
 
+func TestPersonal_SetWebhook_Invalid(t *testing.T) {
+	client := &clienttest{}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	err := personal.SetWebhook(context.Background(), "http://domain/webhook")
+	expectError(t, err, "webhook must use https scheme")
+	expectTrue(t, client.Req == nil)
+}
+
+func TestPersonal_SetWebhook_Body(t *testing.T) {
+	client := &clienttest{Resp: okResponse(`{}`)}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	err := personal.SetWebhook(context.Background(), "https://domain/webhook")
+	expectNoError(t, err)
+
+	bts, err := ioutil.ReadAll(client.Req.Body)
+	expectNoError(t, err)
+	expectEquals(t, client.Req.Method, http.MethodPost)
+	expectEquals(t, client.Req.URL.Path, "/personal/webhook")
+	expectEquals(t, string(bts), `{"webHookUrl":"https://domain/webhook"}`)
+}
+
+func TestPersonal_Webhook(t *testing.T) {
+	client := &clienttest{Resp: okResponse(personalResponseBody)}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	wh, err := personal.Webhook(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, wh, "https://url/leading/to/the/webhook")
+	expectEquals(t, client.Req.URL.Path, "/personal/client-info")
+
+	client.Resp = &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(failResponseBody))),
+	}
+
+	_, err = personal.Webhook(context.Background())
+	expectError(t, err, "mono error: go away")
+}
+
+func TestPersonal_ClearWebhook(t *testing.T) {
+	client := &clienttest{Resp: okResponse(`{}`)}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	expectNoError(t, personal.ClearWebhook(context.Background()))
+
+	bts, err := ioutil.ReadAll(client.Req.Body)
+	expectNoError(t, err)
+	expectEquals(t, string(bts), `{"webHookUrl":""}`)
+}
+
+func TestPersonal_SetWebhook_EmptyClears(t *testing.T) {
+	client := &clienttest{Resp: okResponse(`{}`)}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	expectNoError(t, personal.SetWebhook(context.Background(), ""))
+
+	bts, err := ioutil.ReadAll(client.Req.Body)
+	expectNoError(t, err)
+	expectEquals(t, string(bts), `{"webHookUrl":""}`)
+}
+
+func TestPersonal_RotateWebhook_Succ(t *testing.T) {
+	client := &seqclienttest{Resps: []*http.Response{
+		okResponse(personalResponseBody),
+		okResponse(""),
+		okResponse(`{}`),
+	}}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	previous, err := personal.RotateWebhook(context.Background(), "https://new/webhook")
+	expectNoError(t, err)
+	expectEquals(t, previous, "https://url/leading/to/the/webhook")
+	expectEquals(t, len(client.Reqs), 3)
+
+	handshake := client.Reqs[1]
+	expectEquals(t, handshake.Method, http.MethodGet)
+	expectEquals(t, handshake.URL.String(), "https://new/webhook")
+	expectEquals(t, handshake.Header.Get("X-Token"), "")
+
+	bts, err := ioutil.ReadAll(client.Reqs[2].Body)
+	expectNoError(t, err)
+	expectEquals(t, string(bts), `{"webHookUrl":"https://new/webhook"}`)
+}
+
+func TestPersonal_RotateWebhook_Fail(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := newPersonal(t, "api-token").RotateWebhook(ctx, "http://new/webhook")
+	expectError(t, err, "webhook must use https scheme")
+
+	client := &seqclienttest{Resps: []*http.Response{
+		okResponse(personalResponseBody),
+		{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewReader(nil))},
+	}}
+	personal := newPersonal(t, "api-token", mono.WithClient(client))
+
+	_, err = personal.RotateWebhook(ctx, "https://new/webhook")
+	expectError(t, err, "webhook answered handshake with 404")
+	expectEquals(t, len(client.Reqs), 2)
+
+	client = &seqclienttest{
+		Resps: []*http.Response{okResponse(personalResponseBody), nil},
+		Errs:  []error{nil, errors.New("boo")},
+	}
+	personal = newPersonal(t, "api-token", mono.WithClient(client))
+
+	_, err = personal.RotateWebhook(ctx, "https://new/webhook")
+	expectError(t, err, "failed to make handshake: boo")
+}
+
 var webhookBody = `{
 	"type": "StatementItem",
 	"data": {
@@ -268,7 +466,7 @@ func TestPersonal_ParseWebhook_Succ(t *testing.T) {
 	apiToken := "api-token"
 	req := httptest.NewRequest(http.MethodGet, "/", bytes.NewReader([]byte(webhookBody)))
 
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	actual, err := personal.ParseWebhook(ctx, req.Body)
 	expectNoError(t, err)
@@ -281,7 +479,7 @@ func TestPersonal_ParseWebhook_Fail(t *testing.T) {
 
 	ctx := context.Background()
 	apiToken := "api-token"
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client), mono.WithUnmarshaller(um))
+	personal := newPersonal(t, apiToken, mono.WithClient(client), mono.WithUnmarshaller(um))
 
 	req := httptest.NewRequest(http.MethodGet, "/", &badReader{})
 
@@ -304,7 +502,7 @@ func TestPersonal_ListenForWebhooks_Succ(t *testing.T) {
 	client := &clienttest{}
 
 	apiToken := "api-token"
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	whChan, handlerFunc := personal.ListenForWebhooks(context.Background())
 
@@ -323,11 +521,42 @@ func TestPersonal_ListenForWebhooks_Succ(t *testing.T) {
 	}
 }
 
+func TestListenForWebhooks_NoToken(t *testing.T) {
+	whChan, handlerFunc := mono.ListenForWebhooks(context.Background(), mono.WithWebhookBufferSize(1))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(webhookBody)))
+
+	handlerFunc(w, r)
+	expectEquals(t, w.Code, http.StatusOK)
+
+	select {
+	case <-time.After(time.Millisecond * 10):
+		t.Fatalf("died waiting on the message")
+
+	case wh := <-whChan:
+		expectDeepEquals(t, wh, webhookParsed)
+	}
+}
+
+func TestPersonal_ListenForWebhooks_Handshake(t *testing.T) {
+	personal := newPersonal(t, "api-token", mono.WithClient(&clienttest{}))
+
+	whChan, handlerFunc := personal.ListenForWebhooks(context.Background())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	handlerFunc(w, r)
+	expectEquals(t, w.Code, http.StatusOK)
+	expectEquals(t, len(whChan), 0)
+}
+
 func TestPersonal_ListenForWebhooks_Fail(t *testing.T) {
 	client := &clienttest{}
 
 	apiToken := "api-token"
-	personal := mono.NewPersonal(apiToken, mono.WithClient(client))
+	personal := newPersonal(t, apiToken, mono.WithClient(client))
 
 	_, handlerFunc := personal.ListenForWebhooks(context.Background())
 
@@ -337,3 +566,65 @@ func TestPersonal_ListenForWebhooks_Fail(t *testing.T) {
 	handlerFunc(w, r)
 	expectEquals(t, w.Code, http.StatusInternalServerError)
 }
+
+func BenchmarkPersonal_Statements(b *testing.B) {
+	personal, from, to := benchmarkStatements(b)
+
+	for i := 0; i < b.N; i++ {
+		items, err := personal.Statements(context.Background(), "deadbeef", from, to)
+		if err != nil || len(items) != mono.MaxStatementItems {
+			b.Fatalf("Unexpected result: %d items, %v", len(items), err)
+		}
+	}
+}
+
+func BenchmarkPersonal_StreamStatements(b *testing.B) {
+	personal, from, to := benchmarkStatements(b)
+
+	for i := 0; i < b.N; i++ {
+		var count int
+
+		err := personal.StreamStatements(context.Background(), "deadbeef", from, to, func(mono.StatementItem) error {
+			count++
+			return nil
+		})
+		if err != nil || count != mono.MaxStatementItems {
+			b.Fatalf("Unexpected result: %d items, %v", count, err)
+		}
+	}
+}
+
+// benchmarkStatements serves the full page of statements.
+func benchmarkStatements(b *testing.B) (mono.Personal, time.Time, time.Time) {
+	b.Helper()
+
+	var item mono.StatementItem
+	if err := json.Unmarshal([]byte(statementsResponseBody[1:len(statementsResponseBody)-1]), &item); err != nil {
+		b.Fatal(err)
+	}
+
+	items := make([]mono.StatementItem, mono.MaxStatementItems)
+	for i := range items {
+		items[i] = item
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	client := clientFunc(func(*http.Request) (*http.Response, error) {
+		return okResponse(string(body)), nil
+	})
+
+	personal, err := mono.NewPersonal("api-token", mono.WithClient(client))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	return personal, time.Now().Add(-time.Hour), time.Now()
+}
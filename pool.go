@@ -0,0 +1,351 @@
+package mono
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kudrykv/go-monobank-api/internal/timer"
+)
+
+// TokenLookup finds the personal token of the user.
+type TokenLookup func(ctx context.Context, userID string) (string, error)
+
+// PoolOption allows to change default values for the pool.
+type PoolOption func(*PersonalPool)
+
+// WithPoolOptions sets options for every client of the pool.
+// Without WithClient, clients share the single http.Client.
+func WithPoolOptions(opts ...Option) PoolOption {
+	return func(p *PersonalPool) {
+		p.opts = append(p.opts, opts...)
+	}
+}
+
+// WithIdleTimeout sets how long the client stays in the pool without use. Default is 30 minutes.
+func WithIdleTimeout(d time.Duration) PoolOption {
+	return func(p *PersonalPool) {
+		p.idle = d
+	}
+}
+
+// WithCacheTTL sets how long client info and statements are answered from the cache.
+// Default is PersonalRateLimit, as the bank would not answer sooner anyway. Zero disables the cache.
+func WithCacheTTL(ttl time.Duration) PoolOption {
+	return func(p *PersonalPool) {
+		p.ttl = ttl
+	}
+}
+
+// WithPoolClock allows to replace `time.Now` and waiting for the rate limit with the controlled clock.
+func WithPoolClock(now func() time.Time, wait func(context.Context, time.Duration) error) PoolOption {
+	return func(p *PersonalPool) {
+		p.now = now
+		p.wait = wait
+	}
+}
+
+// PersonalPool keeps the Personal client for every user, creating it on the first use.
+// Each client waits for its own rate limit and has its own cache, so users do not affect each other.
+// It is safe for concurrent use.
+type PersonalPool struct {
+	lookup TokenLookup
+	opts   []Option
+	idle   time.Duration
+	ttl    time.Duration
+	now    func() time.Time
+	wait   func(context.Context, time.Duration) error
+
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+}
+
+type pooledClient struct {
+	personal Personal
+	lastUsed time.Time
+	inFlight int
+}
+
+// NewPersonalPool creates the pool that finds tokens of users with the lookup.
+func NewPersonalPool(lookup TokenLookup, opts ...PoolOption) *PersonalPool {
+	p := &PersonalPool{
+		lookup:  lookup,
+		opts:    []Option{WithClient(&http.Client{})},
+		idle:    30 * time.Minute,
+		ttl:     PersonalRateLimit,
+		now:     time.Now,
+		wait:    timer.Sleep,
+		clients: map[string]*pooledClient{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// For returns the client of the user, creating it when the user has none.
+// It also evicts clients that stayed idle for too long. Every call of the client counts as its use,
+// and clients with calls in flight are never evicted, so keeping the returned client is safe.
+func (p *PersonalPool) For(ctx context.Context, userID string) (Personal, error) {
+	p.mu.Lock()
+
+	now := p.now()
+	p.evictIdle(now)
+
+	if c, ok := p.clients[userID]; ok {
+		c.lastUsed = now
+		p.mu.Unlock()
+
+		return c.personal, nil
+	}
+
+	p.mu.Unlock()
+
+	token, err := p.lookup(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(token) == 0 {
+		return nil, errors.New("api token is required")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[userID]; ok {
+		c.lastUsed = now
+		return c.personal, nil
+	}
+
+	c := &pooledClient{lastUsed: now}
+	c.personal = p.newPersonal(token, c)
+	p.clients[userID] = c
+
+	return c.personal, nil
+}
+
+// Evict removes the client of the user, so the next For looks the token up again.
+func (p *PersonalPool) Evict(userID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.clients, userID)
+}
+
+// EvictIdle removes clients that stayed idle for too long and returns how many were removed.
+func (p *PersonalPool) EvictIdle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.evictIdle(p.now())
+}
+
+// Len returns the number of clients in the pool.
+func (p *PersonalPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.clients)
+}
+
+func (p *PersonalPool) evictIdle(now time.Time) int {
+	evicted := 0
+
+	for userID, c := range p.clients {
+		if c.inFlight == 0 && now.Sub(c.lastUsed) >= p.idle {
+			delete(p.clients, userID)
+			evicted++
+		}
+	}
+
+	return evicted
+}
+
+func (p *PersonalPool) newPersonal(token string, pooled *pooledClient) Personal {
+	c := newCore(p.opts...)
+	c.tokens = StaticToken(token)
+	c.middlewares = append(c.middlewares, p.track(pooled))
+
+	if p.ttl > 0 {
+		cache := &responseCache{ttl: p.ttl, now: p.now, entries: map[string]cachedResponse{}}
+		c.middlewares = append(c.middlewares, cache.middleware(c.tinyClient))
+	}
+
+	limiter := &rateLimiter{interval: PersonalRateLimit, now: p.now, wait: p.wait, last: map[string]time.Time{}}
+	c.middlewares = append(c.middlewares, limiter.middleware(c.metrics))
+
+	return personal{core: c}
+}
+
+// track marks the client used when its call starts and ends, and keeps it in the pool while the call is in flight.
+func (p *PersonalPool) track(pooled *pooledClient) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			p.mu.Lock()
+			pooled.inFlight++
+			pooled.lastUsed = p.now()
+			p.mu.Unlock()
+
+			defer func() {
+				p.mu.Lock()
+				pooled.inFlight--
+				pooled.lastUsed = p.now()
+				p.mu.Unlock()
+			}()
+
+			return next(ctx, call)
+		}
+	}
+}
+
+// rateLimiter spaces calls of client info and statements so the bank does not reject them.
+type rateLimiter struct {
+	interval time.Duration
+	now      func() time.Time
+	wait     func(context.Context, time.Duration) error
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func (l *rateLimiter) middleware(metrics Metrics) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if call.Endpoint != EndpointClientInfo && call.Endpoint != EndpointStatement {
+				return next(ctx, call)
+			}
+
+			slot, prev := l.reserve(call.Endpoint)
+
+			if d := slot.Sub(l.now()); d > 0 {
+				if metrics != nil {
+					metrics.RateLimitWaited(call.Endpoint, d)
+				}
+
+				if err := l.wait(ctx, d); err != nil {
+					l.release(call.Endpoint, slot, prev)
+					return err
+				}
+			}
+
+			return next(ctx, call)
+		}
+	}
+}
+
+// reserve takes the next free slot of the endpoint, returning it along with the previous one.
+func (l *rateLimiter) reserve(endpoint string) (time.Time, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	slot := now
+	prev := l.last[endpoint]
+
+	if !prev.IsZero() && prev.Add(l.interval).After(now) {
+		slot = prev.Add(l.interval)
+	}
+
+	l.last[endpoint] = slot
+
+	return slot, prev
+}
+
+// release gives back the slot of the call that gave up waiting, so it does not delay later calls.
+// The slot stays taken when a later call has already reserved the one after it.
+func (l *rateLimiter) release(endpoint string, slot, prev time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last[endpoint].Equal(slot) {
+		return
+	}
+
+	if prev.IsZero() {
+		delete(l.last, endpoint)
+	} else {
+		l.last[endpoint] = prev
+	}
+}
+
+// responseCache answers client info and statements from recent responses.
+// Setting the webhook drops the cache, as the client info includes the webhook.
+// Calls with NoCache skip the cached response and store the fresh one. Streamed calls keep no body to cache.
+type responseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	response Response
+	expires  time.Time
+}
+
+func (r *responseCache) middleware(client tinyClient) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if call.Endpoint == EndpointWebhook {
+				r.mu.Lock()
+				r.entries = map[string]cachedResponse{}
+				r.mu.Unlock()
+
+				return next(ctx, call)
+			}
+
+			if call.stream != nil || (call.Endpoint != EndpointClientInfo && call.Endpoint != EndpointStatement) {
+				return next(ctx, call)
+			}
+
+			key := call.Method + " " + call.URL
+
+			r.mu.Lock()
+			cached, ok := r.entries[key]
+			r.mu.Unlock()
+
+			if ok && !call.NoCache && r.now().Before(cached.expires) {
+				if err := client.unmarshaller.Unmarshal(cached.response.Body, &call.Result); err == nil {
+					call.Response = cached.response
+					call.Response.Latency, call.Response.Attempts, call.Response.Cached = 0, 0, true
+
+					if client.metrics != nil {
+						client.metrics.CacheHit(call.Endpoint)
+					}
+
+					return nil
+				}
+			}
+
+			if err := next(ctx, call); err != nil {
+				return err
+			}
+
+			r.store(key, call.Response)
+
+			return nil
+		}
+	}
+}
+
+// store keeps the response and drops expired ones, so old statement periods do not pile up.
+func (r *responseCache) store(key string, response Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+
+	for k, entry := range r.entries {
+		if !now.Before(entry.expires) {
+			delete(r.entries, k)
+		}
+	}
+
+	r.entries[key] = cachedResponse{response: response, expires: now.Add(r.ttl)}
+}
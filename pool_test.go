@@ -0,0 +1,281 @@
+package mono_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+type poolclock struct {
+	now    time.Time
+	waited time.Duration
+}
+
+func (c *poolclock) Now() time.Time {
+	return c.now
+}
+
+func (c *poolclock) wait(_ context.Context, d time.Duration) error {
+	c.now = c.now.Add(d)
+	c.waited += d
+
+	return nil
+}
+
+type poolmetrics struct {
+	metricstest
+	Waits     []time.Duration
+	CacheHits int
+}
+
+func (m *poolmetrics) RateLimitWaited(_ string, d time.Duration) {
+	m.Waits = append(m.Waits, d)
+}
+
+func (m *poolmetrics) CacheHit(string) {
+	m.CacheHits++
+}
+
+func TestPersonalPool(t *testing.T) {
+	ctx := context.Background()
+	clk := &poolclock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token-1", mono.UserInfo{Name: "first", Accounts: []mono.Account{{ID: "acc"}}})
+	srv.AddClient("token-2", mono.UserInfo{Name: "second"})
+
+	lookups := 0
+	lookup := func(_ context.Context, userID string) (string, error) {
+		lookups++
+
+		if userID == "nobody" {
+			return "", errors.New("unknown user")
+		}
+
+		return "token-" + userID, nil
+	}
+
+	metrics := &poolmetrics{}
+	pool := mono.NewPersonalPool(lookup,
+		mono.WithPoolOptions(srv.Options()...),
+		mono.WithPoolOptions(mono.WithMetrics(metrics)),
+		mono.WithPoolClock(clk.Now, clk.wait),
+	)
+
+	first, err := pool.For(ctx, "1")
+	expectNoError(t, err)
+
+	_, err = pool.For(ctx, "1")
+	expectNoError(t, err)
+	expectEquals(t, lookups, 1)
+
+	info, err := first.ClientInfo(ctx)
+	expectNoError(t, err)
+	expectEquals(t, info.Name, "first")
+
+	info, err = first.ClientInfo(ctx)
+	expectNoError(t, err)
+	expectEquals(t, info.Name, "first")
+	expectEquals(t, metrics.CacheHits, 1)
+
+	_, err = first.Statements(ctx, "acc", clk.Now().Add(-time.Hour), clk.Now())
+	expectNoError(t, err)
+
+	_, err = first.Statements(ctx, "acc", clk.Now().Add(-2*time.Hour), clk.Now())
+	expectNoError(t, err)
+	expectEquals(t, clk.waited, mono.PersonalRateLimit)
+	expectDeepEquals(t, metrics.Waits, []time.Duration{mono.PersonalRateLimit})
+
+	second, err := pool.For(ctx, "2")
+	expectNoError(t, err)
+
+	info, err = second.ClientInfo(ctx)
+	expectNoError(t, err)
+	expectEquals(t, info.Name, "second")
+	expectEquals(t, clk.waited, mono.PersonalRateLimit)
+	expectEquals(t, pool.Len(), 2)
+
+	_, err = pool.For(ctx, "nobody")
+	expectError(t, err, "unknown user")
+
+	pool.Evict("2")
+	expectEquals(t, pool.Len(), 1)
+
+	clk.now = clk.now.Add(30 * time.Minute)
+	expectEquals(t, pool.EvictIdle(), 1)
+	expectEquals(t, pool.Len(), 0)
+}
+
+func TestPersonalPool_WebhookDropsCache(t *testing.T) {
+	ctx := context.Background()
+	clk := &poolclock{now: time.Unix(1577836800, 0)}
+
+	hook := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer hook.Close()
+
+	srv := monotest.NewServer(monotest.WithClock(clk.Now), monotest.WithWebhookClient(hook.Client()))
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{})
+
+	lookup := func(context.Context, string) (string, error) { return "token", nil }
+	pool := mono.NewPersonalPool(lookup,
+		mono.WithPoolOptions(srv.Options()...),
+		mono.WithPoolClock(clk.Now, clk.wait),
+		mono.WithCacheTTL(time.Hour),
+		mono.WithIdleTimeout(time.Minute),
+	)
+
+	personal, err := pool.For(ctx, "user")
+	expectNoError(t, err)
+
+	webhook, err := personal.Webhook(ctx)
+	expectNoError(t, err)
+	expectEquals(t, webhook, "")
+
+	expectNoError(t, personal.SetWebhook(ctx, hook.URL))
+
+	webhook, err = personal.Webhook(ctx)
+	expectNoError(t, err)
+	expectEquals(t, webhook, hook.URL)
+	expectEquals(t, clk.waited, mono.PersonalRateLimit)
+}
+
+func TestPersonalPool_NoCache(t *testing.T) {
+	ctx := context.Background()
+	clk := &poolclock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Name: "old"})
+
+	metrics := &poolmetrics{}
+	lookup := func(context.Context, string) (string, error) { return "token", nil }
+	pool := mono.NewPersonalPool(lookup,
+		mono.WithPoolOptions(srv.Options()...),
+		mono.WithPoolOptions(mono.WithMetrics(metrics)),
+		mono.WithPoolClock(clk.Now, clk.wait),
+		mono.WithCacheTTL(time.Hour),
+	)
+
+	personal, err := pool.For(ctx, "user")
+	expectNoError(t, err)
+
+	_, err = personal.ClientInfo(ctx)
+	expectNoError(t, err)
+
+	srv.AddClient("token", mono.UserInfo{Name: "new"})
+
+	var fresh, captured mono.Response
+
+	info, err := personal.ClientInfo(ctx, mono.NoCache(), mono.CaptureResponse(&fresh))
+	expectNoError(t, err)
+	expectEquals(t, info.Name, "new")
+	expectEquals(t, metrics.CacheHits, 0)
+	expectEquals(t, fresh.Cached, false)
+	expectEquals(t, fresh.Attempts, 1)
+
+	info, err = personal.ClientInfo(ctx, mono.CaptureResponse(&captured))
+	expectNoError(t, err)
+	expectEquals(t, info.Name, "new")
+	expectEquals(t, metrics.CacheHits, 1)
+	expectEquals(t, captured.StatusCode, http.StatusOK)
+	expectEquals(t, captured.Attempts, 0)
+	expectEquals(t, captured.Latency, time.Duration(0))
+	expectEquals(t, captured.Cached, true)
+}
+
+func TestPersonalPool_KeptClientStaysInPool(t *testing.T) {
+	ctx := context.Background()
+	clk := &poolclock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{Name: "first"})
+
+	var pool *mono.PersonalPool
+
+	evicted := 0
+	wait := func(ctx context.Context, d time.Duration) error {
+		clk.now = clk.now.Add(d)
+		evicted += pool.EvictIdle()
+
+		return nil
+	}
+
+	lookup := func(context.Context, string) (string, error) { return "token", nil }
+	pool = mono.NewPersonalPool(lookup,
+		mono.WithPoolOptions(srv.Options()...),
+		mono.WithPoolClock(clk.Now, wait),
+		mono.WithCacheTTL(0),
+		mono.WithIdleTimeout(30*time.Second),
+	)
+
+	personal, err := pool.For(ctx, "user")
+	expectNoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		clk.now = clk.now.Add(20 * time.Second)
+
+		_, err = personal.ClientInfo(ctx)
+		expectNoError(t, err)
+		expectEquals(t, pool.EvictIdle(), 0)
+	}
+
+	expectEquals(t, evicted, 0)
+	expectEquals(t, pool.Len(), 1)
+
+	clk.now = clk.now.Add(30 * time.Second)
+	expectEquals(t, pool.EvictIdle(), 1)
+}
+
+func TestPersonalPool_CanceledWaitReleasesSlot(t *testing.T) {
+	ctx := context.Background()
+	clk := &poolclock{now: time.Unix(1577836800, 0)}
+	srv := monotest.NewServer(monotest.WithClock(clk.Now))
+
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{})
+
+	canceled := false
+	wait := func(ctx context.Context, d time.Duration) error {
+		if canceled {
+			return context.Canceled
+		}
+
+		return clk.wait(ctx, d)
+	}
+
+	lookup := func(context.Context, string) (string, error) { return "token", nil }
+	pool := mono.NewPersonalPool(lookup,
+		mono.WithPoolOptions(srv.Options()...),
+		mono.WithPoolClock(clk.Now, wait),
+		mono.WithCacheTTL(0),
+	)
+
+	personal, err := pool.For(ctx, "user")
+	expectNoError(t, err)
+
+	_, err = personal.ClientInfo(ctx)
+	expectNoError(t, err)
+
+	canceled = true
+	_, err = personal.ClientInfo(ctx)
+	expectEquals(t, errors.Is(err, context.Canceled), true)
+
+	canceled = false
+	_, err = personal.ClientInfo(ctx)
+	expectNoError(t, err)
+	expectEquals(t, clk.waited, mono.PersonalRateLimit)
+}
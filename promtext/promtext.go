@@ -0,0 +1,248 @@
+// Package promtext collects client metrics and serves them in the Prometheus text format,
+// without depending on the Prometheus client:
+//
+//	registry := promtext.NewRegistry()
+//	personal, err := mono.NewPersonal(token, mono.WithMetrics(registry))
+//
+//	http.Handle("/metrics", registry)
+package promtext
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// DefaultBuckets are upper bounds of latency histograms in seconds.
+func DefaultBuckets() []float64 {
+	return []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+}
+
+// Option allows to change default values for the registry.
+type Option func(*Registry)
+
+// WithBuckets sets upper bounds of histograms in seconds.
+func WithBuckets(buckets ...float64) Option {
+	return func(r *Registry) {
+		r.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(r.buckets)
+	}
+}
+
+// WithNamespace sets the prefix of metric names. Default is `monobank`.
+func WithNamespace(namespace string) Option {
+	return func(r *Registry) {
+		r.namespace = namespace
+	}
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// family is the metric with all its label sets.
+type family struct {
+	counters   map[string]float64
+	histograms map[string]*histogram
+}
+
+func (f *family) labels() []string {
+	keys := make([]string, 0, len(f.counters)+len(f.histograms))
+
+	for k := range f.counters {
+		keys = append(keys, k)
+	}
+
+	for k := range f.histograms {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Registry implements mono.Metrics and serves collected values as http.Handler.
+// It is safe for concurrent use.
+type Registry struct {
+	namespace string
+	buckets   []float64
+
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry creates the empty registry.
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{
+		namespace: "monobank",
+		buckets:   DefaultBuckets(),
+		families:  map[string]*family{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// RequestDone counts the request and its latency.
+func (r *Registry) RequestDone(endpoint string, status int, duration time.Duration) {
+	labels := label("endpoint", endpoint) + "," + label("status", strconv.Itoa(status))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add("requests_total", labels, 1)
+	r.observe("request_duration_seconds", labels, duration)
+}
+
+// Retried counts the repeated request.
+func (r *Registry) Retried(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add("retries_total", label("endpoint", endpoint), 1)
+}
+
+// RateLimitWaited counts the time spent waiting for the rate limit.
+func (r *Registry) RateLimitWaited(endpoint string, wait time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.observe("rate_limit_wait_seconds", label("endpoint", endpoint), wait)
+}
+
+// CacheHit counts the call answered from the cache.
+func (r *Registry) CacheHit(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add("cache_hits_total", label("endpoint", endpoint), 1)
+}
+
+// Webhook counts the webhook event.
+func (r *Registry) Webhook(event mono.WebhookEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add("webhooks_total", label("event", string(event)), 1)
+}
+
+// ServeHTTP writes all metrics in the text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.Write(w)
+}
+
+// Write writes all metrics in the text exposition format, sorted by name and labels.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+
+	for _, name := range names {
+		f := r.families[name]
+		full := r.namespace + "_" + name
+
+		if f.counters != nil {
+			sb.WriteString("# TYPE " + full + " counter\n")
+		} else {
+			sb.WriteString("# TYPE " + full + " histogram\n")
+		}
+
+		for _, labels := range f.labels() {
+			if h, ok := f.histograms[labels]; ok {
+				r.writeHistogram(&sb, full, labels, h)
+			} else {
+				sb.WriteString(full + "{" + labels + "} " + formatFloat(f.counters[labels]) + "\n")
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func (r *Registry) writeHistogram(sb *strings.Builder, name, labels string, h *histogram) {
+	for i, bound := range r.buckets {
+		le := label("le", formatFloat(bound))
+		sb.WriteString(name + "_bucket{" + labels + "," + le + "} " + strconv.FormatUint(h.counts[i], 10) + "\n")
+	}
+
+	count := strconv.FormatUint(h.count, 10)
+
+	sb.WriteString(name + "_bucket{" + labels + "," + label("le", "+Inf") + "} " + count + "\n")
+	sb.WriteString(name + "_sum{" + labels + "} " + formatFloat(h.sum) + "\n")
+	sb.WriteString(name + "_count{" + labels + "} " + count + "\n")
+}
+
+func (r *Registry) family(name string) *family {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{}
+		r.families[name] = f
+	}
+
+	return f
+}
+
+func (r *Registry) add(name, labels string, v float64) {
+	f := r.family(name)
+	if f.counters == nil {
+		f.counters = map[string]float64{}
+	}
+
+	f.counters[labels] += v
+}
+
+func (r *Registry) observe(name, labels string, d time.Duration) {
+	f := r.family(name)
+	if f.histograms == nil {
+		f.histograms = map[string]*histogram{}
+	}
+
+	h, ok := f.histograms[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(r.buckets))}
+		f.histograms[labels] = h
+	}
+
+	seconds := d.Seconds()
+
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += seconds
+}
+
+// label formats the label pair, escaping only what the text format requires: backslash, quote and new line.
+func label(name, value string) string {
+	return name + `="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,116 @@
+package promtext_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+	"github.com/kudrykv/go-monobank-api/promtext"
+)
+
+func TestRegistry(t *testing.T) {
+	r := promtext.NewRegistry(promtext.WithBuckets(1, 0.1), promtext.WithNamespace("mono"))
+
+	r.RequestDone(mono.EndpointStatement, http.StatusOK, 50*time.Millisecond)
+	r.RequestDone(mono.EndpointStatement, http.StatusOK, 2*time.Second)
+	r.RequestDone(mono.EndpointStatement, http.StatusTooManyRequests, 500*time.Millisecond)
+	r.Retried(mono.EndpointStatement)
+	r.RateLimitWaited(mono.EndpointStatement, time.Minute)
+	r.CacheHit(mono.EndpointClientInfo)
+	r.Webhook(mono.WebhookReceived)
+	r.Webhook(mono.WebhookReceived)
+	r.Webhook(mono.WebhookDropped)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	expected := `# TYPE mono_cache_hits_total counter
+mono_cache_hits_total{endpoint="client-info"} 1
+# TYPE mono_rate_limit_wait_seconds histogram
+mono_rate_limit_wait_seconds_bucket{endpoint="statement",le="0.1"} 0
+mono_rate_limit_wait_seconds_bucket{endpoint="statement",le="1"} 0
+mono_rate_limit_wait_seconds_bucket{endpoint="statement",le="+Inf"} 1
+mono_rate_limit_wait_seconds_sum{endpoint="statement"} 60
+mono_rate_limit_wait_seconds_count{endpoint="statement"} 1
+# TYPE mono_request_duration_seconds histogram
+mono_request_duration_seconds_bucket{endpoint="statement",status="200",le="0.1"} 1
+mono_request_duration_seconds_bucket{endpoint="statement",status="200",le="1"} 1
+mono_request_duration_seconds_bucket{endpoint="statement",status="200",le="+Inf"} 2
+mono_request_duration_seconds_sum{endpoint="statement",status="200"} 2.05
+mono_request_duration_seconds_count{endpoint="statement",status="200"} 2
+mono_request_duration_seconds_bucket{endpoint="statement",status="429",le="0.1"} 0
+mono_request_duration_seconds_bucket{endpoint="statement",status="429",le="1"} 1
+mono_request_duration_seconds_bucket{endpoint="statement",status="429",le="+Inf"} 1
+mono_request_duration_seconds_sum{endpoint="statement",status="429"} 0.5
+mono_request_duration_seconds_count{endpoint="statement",status="429"} 1
+# TYPE mono_requests_total counter
+mono_requests_total{endpoint="statement",status="200"} 2
+mono_requests_total{endpoint="statement",status="429"} 1
+# TYPE mono_retries_total counter
+mono_retries_total{endpoint="statement"} 1
+# TYPE mono_webhooks_total counter
+mono_webhooks_total{event="dropped"} 1
+mono_webhooks_total{event="received"} 2
+`
+
+	if w.Body.String() != expected {
+		t.Fatalf("Unexpected output:\n%s", w.Body)
+	}
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Unexpected content type: %s", ct)
+	}
+}
+
+func TestRegistry_LabelEscaping(t *testing.T) {
+	r := promtext.NewRegistry()
+	r.Retried("a\"b\\c\nd\tж")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	expected := "monobank_retries_total{endpoint=\"a\\\"b\\\\c\\nd\tж\"} 1\n"
+	if !strings.Contains(w.Body.String(), expected) {
+		t.Fatalf("Expected %q in the output:\n%s", expected, w.Body)
+	}
+}
+
+func TestRegistry_Client(t *testing.T) {
+	srv := monotest.NewServer()
+	defer srv.Close()
+
+	srv.AddClient("token", mono.UserInfo{})
+
+	r := promtext.NewRegistry()
+	personal, err := mono.NewPersonal("token", append(srv.Options(), mono.WithMetrics(r))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = personal.ClientInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := personal.ClientInfo(context.Background()); err == nil {
+		t.Fatal("Expected the rate limit error")
+	}
+
+	var sb strings.Builder
+	if err := r.Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		`monobank_requests_total{endpoint="client-info",status="200"} 1`,
+		`monobank_requests_total{endpoint="client-info",status="429"} 1`,
+	} {
+		if !strings.Contains(sb.String(), line+"\n") {
+			t.Fatalf("Expected %s in:\n%s", line, sb.String())
+		}
+	}
+}
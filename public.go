@@ -21,9 +21,11 @@ func NewPublic(opts ...Option) Public {
 	return public{core: newCore(opts...)}
 }
 
-func (p public) Currency(ctx context.Context) ([]CurrencyInfo, error) {
+func (p public) Currency(ctx context.Context, opts ...CallOption) ([]CurrencyInfo, error) {
+	url := p.domain + "/bank/currency"
+
 	var currencies []CurrencyInfo
-	if err := p.request(ctx, http.MethodGet, p.domain+"/bank/currency", nil, &currencies); err != nil {
+	if err := p.request(ctx, EndpointCurrency, http.MethodGet, url, nil, &currencies, opts...); err != nil {
 		return nil, err
 	}
 
@@ -1,55 +1,241 @@
 package mono
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 type tinyClient struct {
-	token        string
+	tokens       TokenSource
+	refresh      func(ctx context.Context) error
 	client       HTTPClient
+	marshaller   Marshaller
 	unmarshaller Unmarshaller
+	middlewares  []Middleware
+	logger       Logger
+	logBodies    bool
+	metrics      Metrics
+	tracer       Tracer
+
+	maxResponseSize int64
+}
+
+func (c tinyClient) request(
+	ctx context.Context, endpoint, method, url string, payload, dst interface{}, opts ...CallOption,
+) error {
+	return c.call(ctx, &Call{Endpoint: endpoint, Method: method, URL: url, Payload: payload, Result: dst}, opts...)
+}
+
+func (c tinyClient) call(ctx context.Context, call *Call, opts ...CallOption) error {
+	if call.Header == nil {
+		call.Header = http.Header{}
+	}
+
+	for _, opt := range opts {
+		opt(call)
+	}
+
+	if call.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, call.timeout)
+		defer cancel()
+	}
+
+	handler := c.do
+	if c.logger != nil {
+		handler = c.logMiddleware(handler)
+	}
+
+	if c.metrics != nil {
+		handler = c.metricsMiddleware(handler)
+	}
+
+	if c.refresh != nil {
+		handler = c.refreshMiddleware(handler)
+	}
+
+	handler = chain(handler, c.middlewares)
+
+	if c.tracer != nil {
+		handler = c.traceMiddleware(handler)
+	}
+
+	err := handler(ctx, call)
+
+	if call.capture != nil {
+		call.Response.Attempts = call.Attempt
+		*call.capture = call.Response
+		call.capture.Header = call.Response.Header.Clone()
+		call.capture.Body = append([]byte(nil), call.Response.Body...)
+	}
+
+	return err
 }
 
-func (c tinyClient) request(ctx context.Context, method, url string, body io.Reader, dst interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+func (c tinyClient) do(ctx context.Context, call *Call) error {
+	var body io.Reader
+
+	call.Attempt++
+	call.Response = Response{}
+	call.requestBody = nil
+	call.responseBytes = 0
+	call.token = ""
+
+	if c.tokens != nil {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return &Error{Kind: ErrorKindToken, Err: err}
+		}
+
+		call.token = token
+	}
+
+	if call.Payload != nil {
+		bts, err := c.marshaller.Marshal(call.Payload)
+		if err != nil {
+			return &Error{Kind: ErrorKindMarshal, Err: err}
+		}
+
+		call.requestBody = bts
+		body = bytes.NewReader(bts)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, call.Method, call.URL, body)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+		return &Error{Kind: ErrorKindRequest, Err: err}
 	}
 
-	if len(c.token) > 0 {
-		req.Header.Add("X-Token", c.token)
+	for key, values := range call.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if len(call.token) > 0 {
+		req.Header.Set("X-Token", call.token)
 	}
 
+	started := time.Now()
+
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %v", err)
+		return &Error{Kind: ErrorKindTransport, Err: err}
 	}
 
-	bts, err := ioutil.ReadAll(resp.Body)
+	// Failed reads return early, and the body must still be closed to free the connection.
+	// Closing it again below only reports the error.
+	defer func() { _ = resp.Body.Close() }()
+
+	reader := &bodyReader{r: resp.Body, limit: c.maxResponseSize}
+
+	if call.stream != nil && resp.StatusCode == http.StatusOK {
+		return c.readStream(call, resp, reader, started)
+	}
+
+	bts, err := ioutil.ReadAll(reader)
+	call.responseBytes = reader.n
+
 	if err != nil {
-		return fmt.Errorf("failed to read body: %v", err)
+		return &Error{Kind: ErrorKindRead, StatusCode: resp.StatusCode, Err: err}
 	}
 
 	if err := resp.Body.Close(); err != nil {
-		return fmt.Errorf("failed to close the body: %v", err)
+		return &Error{Kind: ErrorKindClose, StatusCode: resp.StatusCode, Err: err}
 	}
 
+	call.Response = Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: bts, Latency: time.Since(started)}
+
 	if resp.StatusCode != http.StatusOK {
 		var derp errorMono
 		if err := c.unmarshaller.Unmarshal(bts, &derp); err != nil {
-			return fmt.Errorf("failed to unmarshal body: %v", err)
+			return &Error{Kind: ErrorKindUnmarshal, StatusCode: resp.StatusCode, Err: err}
 		}
 
-		return fmt.Errorf("mono error: %s", derp.Description)
+		return &Error{Kind: ErrorKindAPI, StatusCode: resp.StatusCode, Description: derp.Description}
 	}
 
-	if err := c.unmarshaller.Unmarshal(bts, &dst); err != nil {
-		return fmt.Errorf("failed to unmarshal body: %v", err)
+	if err := c.unmarshaller.Unmarshal(bts, &call.Result); err != nil {
+		return &Error{Kind: ErrorKindUnmarshal, StatusCode: resp.StatusCode, Err: err}
 	}
 
 	return nil
 }
+
+// readStream passes the body to the stream of the call without buffering it.
+// Read errors, including the body outgrowing the limit, take over errors of the stream they caused.
+func (c tinyClient) readStream(call *Call, resp *http.Response, reader *bodyReader, started time.Time) error {
+	err := call.stream(reader)
+	closeErr := resp.Body.Close()
+
+	call.responseBytes = reader.n
+	call.Response = Response{StatusCode: resp.StatusCode, Header: resp.Header, Latency: time.Since(started)}
+
+	if reader.err != nil {
+		return &Error{Kind: ErrorKindRead, StatusCode: resp.StatusCode, Err: reader.err}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if closeErr != nil {
+		return &Error{Kind: ErrorKindClose, StatusCode: resp.StatusCode, Err: closeErr}
+	}
+
+	return nil
+}
+
+// bodyReader counts read bytes and fails once the body outgrows the limit, keeping the read error.
+type bodyReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+	err   error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	// One byte over the limit is enough to tell the body is too large.
+	if rest := b.limit - b.n + 1; b.limit > 0 && int64(len(p)) > rest {
+		p = p[:rest]
+	}
+
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+
+	if b.limit > 0 && b.n > b.limit {
+		err = fmt.Errorf("body is larger than %d bytes", b.limit)
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		b.err = err
+	}
+
+	return n, err
+}
+
+// refreshMiddleware asks to refresh the token once the bank rejects it, and repeats the request with the new one.
+// The bank answers unknown tokens with 403, so both 401 and 403 count.
+func (c tinyClient) refreshMiddleware(next Handler) Handler {
+	return func(ctx context.Context, call *Call) error {
+		err := next(ctx, call)
+
+		var monoErr *Error
+		if !errors.As(err, &monoErr) || monoErr.Kind != ErrorKindAPI ||
+			(monoErr.StatusCode != http.StatusUnauthorized && monoErr.StatusCode != http.StatusForbidden) {
+			return err
+		}
+
+		if refreshErr := c.refresh(ctx); refreshErr != nil {
+			return err
+		}
+
+		return next(ctx, call)
+	}
+}
@@ -0,0 +1,60 @@
+//go:build go1.18
+// +build go1.18
+
+package mono
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"unicode/utf8"
+)
+
+func FuzzTinyClientRequest_WebhookPayload(f *testing.F) {
+	f.Add("https://domain/webhook")
+	f.Add(`https://domain/"quoted"`)
+	f.Add(`https://domain/back\slash`)
+	f.Add("https://domain/new\nline\ttab\u0000")
+	f.Add("https://домен/вебхук?q=<&> ")
+
+	f.Fuzz(func(t *testing.T, webhook string) {
+		if !utf8.ValidString(webhook) {
+			t.Skip("json replaces invalid UTF-8 with U+FFFD")
+		}
+
+		hct := &httpclienttest{
+			Resp: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{}`))),
+			},
+		}
+
+		client := tinyClient{client: hct, marshaller: marshaller{}, unmarshaller: unmarshaller{}}
+
+		var empty struct{}
+
+		payload := webhookRequest{WebHookURL: webhook}
+
+		err := client.request(context.Background(), "test", http.MethodPost, "https://domain/url", payload, &empty)
+		if err != nil {
+			t.Fatalf("No error expected, got: %v", err)
+		}
+
+		bts, err := ioutil.ReadAll(hct.Req.Body)
+		if err != nil {
+			t.Fatalf("No error expected, got: %v", err)
+		}
+
+		var actual webhookRequest
+		if err := json.Unmarshal(bts, &actual); err != nil {
+			t.Fatalf("Body is not a valid JSON: %s", bts)
+		}
+
+		if actual.WebHookURL != webhook {
+			t.Fatalf("Webhook did not round-trip. Actual: %q, expected: %q", actual.WebHookURL, webhook)
+		}
+	})
+}
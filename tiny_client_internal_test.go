@@ -3,6 +3,7 @@ package mono
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -24,7 +25,7 @@ func TestTinyClientRequest_Succ(t *testing.T) {
 	client := tinyClient{client: hct, unmarshaller: unmarshaller{}}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
 	if err != nil {
 		t.Fatalf("No error expected, got: %v", err)
 	}
@@ -48,7 +49,7 @@ func TestTinyClientRequest_FailMono(t *testing.T) {
 	client := tinyClient{client: hct, unmarshaller: unmarshaller{}}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
 	if err == nil {
 		t.Fatal("Error expected, got nil")
 	}
@@ -60,12 +61,66 @@ func TestTinyClientRequest_FailMono(t *testing.T) {
 	testRequest(t, hct.Req)
 }
 
+func TestTinyClientRequest_Payload(t *testing.T) {
+	hct := &httpclienttest{
+		Resp: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{}`))),
+		},
+	}
+
+	client := tinyClient{client: hct, marshaller: marshaller{}, unmarshaller: unmarshaller{}}
+
+	var empty struct{}
+	payload := webhookRequest{WebHookURL: "https://domain/\"quoted\"\\back\n\u0001<&>"}
+
+	err := client.request(context.Background(), "test", http.MethodPost, "https://domain/url", payload, &empty)
+	if err != nil {
+		t.Fatalf("No error expected, got: %v", err)
+	}
+
+	bts, err := ioutil.ReadAll(hct.Req.Body)
+	if err != nil {
+		t.Fatalf("No error expected, got: %v", err)
+	}
+
+	var actual webhookRequest
+	if err := json.Unmarshal(bts, &actual); err != nil {
+		t.Fatalf("Body is not a valid JSON: %s", bts)
+	}
+
+	if actual != payload {
+		t.Errorf("Payload did not round-trip. Actual: %q", actual.WebHookURL)
+	}
+
+	testRequest(t, hct.Req)
+}
+
+func TestTinyClientRequest_FailMarshal(t *testing.T) {
+	hct := &httpclienttest{}
+	client := tinyClient{client: hct, marshaller: marshtest{Err: errors.New("boo")}, unmarshaller: unmarshaller{}}
+
+	var empty struct{}
+	err := client.request(context.Background(), "test", http.MethodPost, "https://domain/url", webhookRequest{}, &empty)
+	if err == nil {
+		t.Fatal("Error expected, got nil")
+	}
+
+	if err.Error() != "failed to marshal body: boo" {
+		t.Error("Actual error differs from expected. Actual> " + err.Error())
+	}
+
+	if hct.Req != nil {
+		t.Error("Expected no request to be made")
+	}
+}
+
 func TestTinyClientRequest_FailMalformedRequest(t *testing.T) {
 	hct := &httpclienttest{}
 	client := tinyClient{client: hct, unmarshaller: unmarshaller{}}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain:err/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain:err/url", nil, &ultimateAnswer)
 	if err == nil {
 		t.Fatal("Error expected, got nil")
 	}
@@ -80,7 +135,7 @@ func TestTinyClientRequest_FailDoRequest(t *testing.T) {
 	client := tinyClient{client: hct, unmarshaller: unmarshaller{}}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
 	if err == nil {
 		t.Fatal("Error expected, got nil")
 	}
@@ -104,7 +159,7 @@ func TestTinyClientRequest_FailReadAll(t *testing.T) {
 	client := tinyClient{client: hct, unmarshaller: unmarshaller{}}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
 	if err == nil {
 		t.Fatal("Error expected, got nil")
 	}
@@ -116,6 +171,42 @@ func TestTinyClientRequest_FailReadAll(t *testing.T) {
 	testRequest(t, hct.Req)
 }
 
+func TestTinyClientRequest_MaxResponseSize(t *testing.T) {
+	hct := &httpclienttest{
+		Resp: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`4242`))),
+		},
+	}
+
+	client := tinyClient{client: hct, unmarshaller: unmarshaller{}, maxResponseSize: 4}
+
+	var ultimateAnswer int
+
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	if err != nil {
+		t.Fatalf("No error expected for the body of the limit size, got: %v", err)
+	}
+
+	body := &closeTracker{Reader: bytes.NewReader([]byte(`42424`))}
+	hct.Resp.Body = body
+
+	err = client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+
+	var monoErr *Error
+	if !errors.As(err, &monoErr) || monoErr.Kind != ErrorKindRead {
+		t.Fatalf("Expected the read error, got: %v", err)
+	}
+
+	if err.Error() != "failed to read body: body is larger than 4 bytes" {
+		t.Error("Actual error differs from expected. Actual> " + err.Error())
+	}
+
+	if !body.closed {
+		t.Error("Expected the body to be closed")
+	}
+}
+
 func TestTinyClientRequest_FailBodyClose(t *testing.T) {
 	hct := &httpclienttest{
 		Resp: &http.Response{
@@ -128,7 +219,7 @@ func TestTinyClientRequest_FailBodyClose(t *testing.T) {
 	client := tinyClient{client: hct, unmarshaller: unmarshaller{}}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
 	if err == nil {
 		t.Fatal("Error expected, got nil")
 	}
@@ -155,7 +246,7 @@ func TestTinyClientRequest_FailUnmarshalOnBadRequest(t *testing.T) {
 	client := tinyClient{client: hct, unmarshaller: u}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
 	if err == nil {
 		t.Fatal("Error expected, got nil")
 	}
@@ -182,7 +273,7 @@ func TestTinyClientRequest_FailUnmarshalOnOK(t *testing.T) {
 	client := tinyClient{client: hct, unmarshaller: u}
 
 	var ultimateAnswer int
-	err := client.request(context.Background(), http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
+	err := client.request(context.Background(), "test", http.MethodGet, "https://domain/url", nil, &ultimateAnswer)
 	if err == nil {
 		t.Fatal("Error expected, got nil")
 	}
@@ -0,0 +1,137 @@
+package mono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenError is the error of the token source that can be compared with `errors.Is`.
+type TokenError string
+
+func (e TokenError) Error() string {
+	return string(e)
+}
+
+// ErrTokenRevoked is returned by MutableToken after Revoke.
+const ErrTokenRevoked TokenError = "token is revoked"
+
+// TokenSource gives the personal token. The client asks for it before every request,
+// so tokens can be rotated or revoked without creating the client again.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc allows to use the function as TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls the function.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticToken always gives the same token.
+func StaticToken(token string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// EnvToken reads the token from the environment variable on every request.
+func EnvToken(name string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		token := strings.TrimSpace(os.Getenv(name))
+		if len(token) == 0 {
+			return "", errors.New("environment variable " + name + " is empty")
+		}
+
+		return token, nil
+	})
+}
+
+// FileToken reads the token from the file, reading it again once the file changes.
+func FileToken(path string) TokenSource {
+	return &fileToken{path: path}
+}
+
+type fileToken struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+func (f *fileToken) Token(context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %v", err)
+	}
+
+	if len(f.token) > 0 && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.token, nil
+	}
+
+	bts, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %v", err)
+	}
+
+	token := strings.TrimSpace(string(bts))
+	if len(token) == 0 {
+		return "", errors.New("token file " + f.path + " is empty")
+	}
+
+	f.token, f.modTime, f.size = token, info.ModTime(), info.Size()
+
+	return token, nil
+}
+
+// MutableToken is the token that can be replaced or revoked while clients use it.
+type MutableToken struct {
+	mu      sync.RWMutex
+	token   string
+	revoked bool
+}
+
+// NewMutableToken creates the source with the token.
+func NewMutableToken(token string) *MutableToken {
+	return &MutableToken{token: token}
+}
+
+// Token gives the current token, or ErrTokenRevoked.
+func (m *MutableToken) Token(context.Context) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.revoked {
+		return "", ErrTokenRevoked
+	}
+
+	return m.token, nil
+}
+
+// Set replaces the token, restoring it if it was revoked.
+func (m *MutableToken) Set(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.token, m.revoked = token, false
+}
+
+// Revoke makes clients fail without calling the bank until the token is set again.
+func (m *MutableToken) Revoke() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revoked = true
+}
@@ -0,0 +1,122 @@
+package mono_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestStaticToken(t *testing.T) {
+	token, err := mono.StaticToken("token").Token(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, token, "token")
+}
+
+func TestEnvToken(t *testing.T) {
+	const name = "MONO_TEST_TOKEN"
+
+	defer func() { _ = os.Unsetenv(name) }()
+
+	_, err := mono.EnvToken(name).Token(context.Background())
+	expectError(t, err, "environment variable MONO_TEST_TOKEN is empty")
+
+	expectNoError(t, os.Setenv(name, " token\n"))
+
+	token, err := mono.EnvToken(name).Token(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, token, "token")
+}
+
+func TestFileToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mono")
+	expectNoError(t, err)
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "token")
+	source := mono.FileToken(path)
+
+	_, err = source.Token(context.Background())
+	expectErrorStartsWith(t, err, "failed to read token file")
+
+	expectNoError(t, ioutil.WriteFile(path, []byte("first\n"), 0o600))
+
+	token, err := source.Token(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, token, "first")
+
+	expectNoError(t, ioutil.WriteFile(path, []byte("rotated\n"), 0o600))
+
+	token, err = source.Token(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, token, "rotated")
+
+	expectNoError(t, ioutil.WriteFile(path, nil, 0o600))
+
+	_, err = source.Token(context.Background())
+	expectError(t, err, "token file "+path+" is empty")
+}
+
+func TestNewPersonalWithTokenSource(t *testing.T) {
+	client := &seqclienttest{Resps: []*http.Response{okResponse(personalResponseBody), okResponse(personalResponseBody)}}
+	token := mono.NewMutableToken("first")
+
+	personal, err := mono.NewPersonalWithTokenSource(token, mono.WithClient(client))
+	expectNoError(t, err)
+
+	_, err = personal.ClientInfo(context.Background())
+	expectNoError(t, err)
+
+	token.Set("second")
+
+	_, err = personal.ClientInfo(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, client.Reqs[0].Header.Get("X-Token"), "first")
+	expectEquals(t, client.Reqs[1].Header.Get("X-Token"), "second")
+
+	token.Revoke()
+
+	_, err = personal.ClientInfo(context.Background())
+	expectError(t, err, "failed to get token: token is revoked")
+	expectTrue(t, errors.Is(err, mono.ErrTokenRevoked))
+	expectEquals(t, len(client.Reqs), 2)
+}
+
+func TestWithTokenRefresh(t *testing.T) {
+	forbidden := func() *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusForbidden,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"errorDescription": "Unknown 'X-Token'"}`))),
+		}
+	}
+
+	client := &seqclienttest{Resps: []*http.Response{forbidden(), okResponse(personalResponseBody)}}
+	token := mono.NewMutableToken("expired")
+	refresh := func(context.Context) error {
+		token.Set("fresh")
+		return nil
+	}
+
+	personal, err := mono.NewPersonalWithTokenSource(token, mono.WithClient(client), mono.WithTokenRefresh(refresh))
+	expectNoError(t, err)
+
+	_, err = personal.ClientInfo(context.Background())
+	expectNoError(t, err)
+	expectEquals(t, client.Reqs[0].Header.Get("X-Token"), "expired")
+	expectEquals(t, client.Reqs[1].Header.Get("X-Token"), "fresh")
+
+	client = &seqclienttest{Resps: []*http.Response{forbidden()}}
+	failing := func(context.Context) error { return errors.New("boo") }
+	personal = newPersonal(t, "token", mono.WithClient(client), mono.WithTokenRefresh(failing))
+
+	_, err = personal.ClientInfo(context.Background())
+	expectError(t, err, "mono error: Unknown 'X-Token'")
+	expectEquals(t, len(client.Reqs), 1)
+}
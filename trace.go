@@ -0,0 +1,189 @@
+package mono
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Tracer starts spans around API calls and webhook handling.
+// Adapters for tracing libraries, like OpenTelemetry, implement it.
+type Tracer interface {
+	// Start begins the span as the child of the span in the context, and returns the context with the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is the traced operation.
+type Span interface {
+	// SpanContext identifies the span for propagation to the bank and further.
+	SpanContext() SpanContext
+	SetAttribute(key, value string)
+	RecordError(err error)
+	End()
+}
+
+// Span names and attributes the client uses.
+const (
+	SpanCall    = "mono.call"
+	SpanWebhook = "mono.webhook"
+
+	AttributeEndpoint   = "mono.endpoint"
+	AttributeAccount    = "mono.account"
+	AttributeErrorKind  = "mono.error_kind"
+	AttributeMethod     = "http.method"
+	AttributeStatusCode = "http.status_code"
+)
+
+// SpanContext is the W3C trace context of the span.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid tells whether both IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Traceparent formats the context as the `traceparent` header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// ParseTraceparent reads the `traceparent` header value.
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	var sc SpanContext
+
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || !isLowerHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return sc, errors.New("invalid traceparent version")
+	}
+
+	// Lengths are checked before decoding, as the header comes from anyone who can reach the webhook.
+	if !isLowerHex(parts[1], 2*len(sc.TraceID)) {
+		return sc, errors.New("invalid trace id")
+	}
+
+	if !isLowerHex(parts[2], 2*len(sc.SpanID)) {
+		return sc, errors.New("invalid span id")
+	}
+
+	if !isLowerHex(parts[3], 2) {
+		return sc, errors.New("invalid trace flags")
+	}
+
+	_, _ = hex.Decode(sc.TraceID[:], []byte(parts[1]))
+	_, _ = hex.Decode(sc.SpanID[:], []byte(parts[2]))
+
+	flags, _ := strconv.ParseUint(parts[3], 16, 8)
+
+	sc.Sampled = flags&1 == 1
+
+	if !sc.IsValid() {
+		return sc, errors.New("trace and span ids must not be zero")
+	}
+
+	return sc, nil
+}
+
+// isLowerHex tells whether s is exactly n lowercase hex digits, the only form the W3C format allows.
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns the context carrying the span context, which becomes the parent of new spans.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context the context carries.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
+
+// HashAccount hides the account ID in span attributes, keeping spans of the same account linkable.
+func HashAccount(account string) string {
+	sum := sha256.Sum256([]byte(account))
+	return hex.EncodeToString(sum[:8])
+}
+
+// traceMiddleware opens the span around the whole call and passes the trace context to the bank.
+func (c tinyClient) traceMiddleware(next Handler) Handler {
+	return func(ctx context.Context, call *Call) error {
+		ctx, span := c.tracer.Start(ctx, SpanCall)
+		defer span.End()
+
+		span.SetAttribute(AttributeEndpoint, call.Endpoint)
+		span.SetAttribute(AttributeMethod, call.Method)
+
+		if len(call.Account) > 0 {
+			span.SetAttribute(AttributeAccount, HashAccount(call.Account))
+		}
+
+		if sc := span.SpanContext(); sc.IsValid() {
+			call.Header.Set("traceparent", sc.Traceparent())
+		}
+
+		err := next(ctx, call)
+
+		if call.Response.StatusCode != 0 {
+			span.SetAttribute(AttributeStatusCode, strconv.Itoa(call.Response.StatusCode))
+		}
+
+		if err != nil {
+			var monoErr *Error
+			if errors.As(err, &monoErr) {
+				span.SetAttribute(AttributeErrorKind, string(monoErr.Kind))
+			}
+
+			span.RecordError(err)
+		}
+
+		return err
+	}
+}
+
+// startWebhookSpan continues the trace of the incoming webhook request.
+func (c tinyClient) startWebhookSpan(r *http.Request) (context.Context, Span) {
+	ctx := r.Context()
+
+	if sc, err := ParseTraceparent(r.Header.Get("traceparent")); err == nil {
+		ctx = ContextWithSpanContext(ctx, sc)
+	}
+
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return c.tracer.Start(ctx, SpanWebhook)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SpanContext() SpanContext    { return SpanContext{} }
+func (noopSpan) SetAttribute(string, string) {}
+func (noopSpan) RecordError(error)           {}
+func (noopSpan) End()                        {}
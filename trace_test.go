@@ -0,0 +1,166 @@
+package mono_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mono "github.com/kudrykv/go-monobank-api"
+	"github.com/kudrykv/go-monobank-api/monotest"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := mono.ParseTraceparent(traceparent)
+	expectNoError(t, err)
+	expectTrue(t, sc.Sampled)
+	expectEquals(t, sc.Traceparent(), traceparent)
+
+	for value, expected := range map[string]string{
+		"": "invalid traceparent version",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":     "invalid traceparent version",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-ext": "invalid traceparent version",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01":       "invalid trace id",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902zz-01":     "invalid span id",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-x":      "invalid trace flags",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0F":     "invalid trace flags",
+		"0A-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":     "invalid traceparent version",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01":     "trace and span ids must not be zero",
+	} {
+		_, err := mono.ParseTraceparent(value)
+		expectError(t, err, expected)
+	}
+
+	_, err = mono.ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+	expectNoError(t, err)
+}
+
+func TestWithTracer_Call(t *testing.T) {
+	client := &clienttest{Resp: &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"errorDescription": "Too many requests"}`))),
+	}}
+	tracer := monotest.NewTracer()
+	personal := newPersonal(t, "token", mono.WithClient(client), mono.WithTracer(tracer))
+
+	parent, err := mono.ParseTraceparent(traceparent)
+	expectNoError(t, err)
+
+	ctx := mono.ContextWithSpanContext(context.Background(), parent)
+
+	_, err = personal.Statements(ctx, "acc", time.Now().Add(-time.Hour), time.Now())
+	expectError(t, err, "mono error: Too many requests")
+
+	spans := tracer.Spans()
+	expectEquals(t, len(spans), 1)
+
+	span := spans[0]
+	expectEquals(t, span.Name, mono.SpanCall)
+	expectEquals(t, span.Parent, parent)
+	expectEquals(t, span.SpanContext.TraceID, parent.TraceID)
+	expectTrue(t, span.Ended)
+	expectDeepEquals(t, span.Attributes, map[string]string{
+		mono.AttributeEndpoint:   mono.EndpointStatement,
+		mono.AttributeMethod:     http.MethodGet,
+		mono.AttributeAccount:    mono.HashAccount("acc"),
+		mono.AttributeStatusCode: "429",
+		mono.AttributeErrorKind:  string(mono.ErrorKindAPI),
+	})
+	expectEquals(t, len(span.Errors), 1)
+	expectEquals(t, client.Req.Header.Get("traceparent"), span.SpanContext.Traceparent())
+}
+
+func TestWithTracer_Webhook(t *testing.T) {
+	tracer := monotest.NewTracer()
+	personal := newPersonal(t, "token", mono.WithClient(&clienttest{}), mono.WithTracer(tracer))
+
+	whChan, handlerFunc := personal.ListenForWebhooks(context.Background())
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(webhookBody)))
+	r.Header.Set("traceparent", traceparent)
+
+	handlerFunc(httptest.NewRecorder(), r)
+	handlerFunc(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", &badReadCloser{}))
+
+	wh := <-whChan
+
+	spans := tracer.Spans()
+	expectEquals(t, len(spans), 2)
+	expectEquals(t, spans[0].Name, mono.SpanWebhook)
+	expectEquals(t, spans[0].Parent.Traceparent(), traceparent)
+	expectEquals(t, spans[0].Attributes[mono.AttributeAccount], mono.HashAccount(wh.Data.AccountID))
+	expectTrue(t, spans[0].Ended)
+	expectTrue(t, !spans[1].Parent.IsValid())
+	expectEquals(t, len(spans[1].Errors), 1)
+}
+
+func TestParseTraceparent_IDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "overlong trace id",
+			value:    "00-4bf92f3577b34da6a3ce929d0e0e4736ab-00f067aa0ba902b7-01",
+			expected: "invalid trace id",
+		},
+		{
+			name:     "short trace id",
+			value:    "00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+			expected: "invalid trace id",
+		},
+		{
+			name:     "uppercase trace id",
+			value:    "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			expected: "invalid trace id",
+		},
+		{
+			name:     "non-hex trace id",
+			value:    "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+			expected: "invalid trace id",
+		},
+		{
+			name:     "overlong span id",
+			value:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7ab-01",
+			expected: "invalid span id",
+		},
+		{
+			name:     "short span id",
+			value:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba9-01",
+			expected: "invalid span id",
+		},
+		{
+			name:     "uppercase span id",
+			value:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00F067AA0BA902B7-01",
+			expected: "invalid span id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mono.ParseTraceparent(tt.value)
+			expectError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestListenForWebhooks_MalformedTraceparent(t *testing.T) {
+	personal := newPersonal(t, "api-token", mono.WithClient(&clienttest{}))
+	whChan, handlerFunc := personal.ListenForWebhooks(context.Background())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(webhookBody)))
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736ffff-00f067aa0ba902b7ffff-01")
+
+	handlerFunc(w, r)
+	expectEquals(t, w.Code, http.StatusOK)
+
+	wh := <-whChan
+	expectDeepEquals(t, wh, webhookParsed)
+}
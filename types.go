@@ -26,6 +26,8 @@ type UserInfo struct {
 type Account struct {
 	// Identifier of the account.
 	ID string `json:"id"`
+	// IBAN of the account.
+	IBAN string `json:"iban"`
 	// Balance in the minimal units -- cents of the corresponding currency.
 	Balance int64 `json:"balance"`
 	// Credit limit.
@@ -55,12 +57,21 @@ type StatementItem struct {
 	// Amount in transaction currency in the minimal units -- cents of the corresponding currency.
 	OperationAmount     int64 `json:"operationAmount"`
 	CurrencyCodeISO4217 int   `json:"currencyCode"`
-	// Commission rate in transaction's currency in the minimal units -- cents of the corresponding currency.
+	// Commission rate in account currency in the minimal units -- cents of the corresponding currency.
+	// Amount already includes it.
 	CommissionRate int64 `json:"commissionRate"`
 	// Cashback amount in account currency in the minimal units -- cents of the corresponding currency.
 	CashbackAmount int64 `json:"cashbackAmount"`
 	// Balance in the minimal units -- cents of the corresponding currency.
 	Balance int64 `json:"balance"`
+	// Comment the sender left for the transfer.
+	Comment string `json:"comment"`
+	// Name of the counterparty of the transfer.
+	CounterName string `json:"counterName"`
+	// IBAN of the counterparty of the transfer.
+	CounterIBAN string `json:"counterIban"`
+	// EDRPOU, the Ukrainian company registration code, of the counterparty of the transfer.
+	CounterEdrpou string `json:"counterEdrpou"`
 }
 
 // CurrencyInfo specifies single currency rate.
@@ -87,6 +98,10 @@ type WebhookStatementItem struct {
 	StatementItem StatementItem `json:"statementItem"`
 }
 
+type webhookRequest struct {
+	WebHookURL string `json:"webHookUrl"`
+}
+
 type errorMono struct {
 	Description string `json:"errorDescription"`
 }
@@ -1,6 +1,7 @@
 package mono_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -10,3 +11,18 @@ import (
 func TestTime_Time(t *testing.T) {
 	expectEquals(t, mono.Time(123).Time(), time.Unix(123, 0))
 }
+
+func TestStatementItem_CommissionIncludedInAmount(t *testing.T) {
+	// The transfer of 100.00 UAH with the 0.50 UAH commission, as the bank returns it.
+	statement := `[
+		{"id":"b","time":200,"mcc":4829,"amount":-10050,"operationAmount":-10000,"currencyCode":980,` +
+		`"commissionRate":50,"balance":89950},
+		{"id":"a","time":100,"mcc":4829,"amount":100000,"operationAmount":100000,"currencyCode":980,"balance":100000}
+	]`
+
+	var items []mono.StatementItem
+	expectNoError(t, json.Unmarshal([]byte(statement), &items))
+
+	expectEquals(t, items[0].Amount, items[0].OperationAmount-items[0].CommissionRate)
+	expectDeepEquals(t, mono.VerifyBalances(mono.Account{ID: "acc", Balance: 89950}, items), []mono.BalanceIssue{})
+}
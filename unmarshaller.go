@@ -2,6 +2,13 @@ package mono
 
 import "encoding/json"
 
+type marshaller struct {
+}
+
+func (m marshaller) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
 type unmarshaller struct {
 }
 
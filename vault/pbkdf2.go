@@ -0,0 +1,43 @@
+package vault
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// pbkdf2 derives the key of keyLen bytes with PBKDF2-HMAC-SHA256 as defined in RFC 8018.
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	size := prf.Size()
+	blocks := (keyLen + size - 1) / size
+
+	var index [4]byte
+
+	key := make([]byte, 0, blocks*size)
+	u := make([]byte, size)
+
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(index[:], uint32(block))
+		prf.Write(index[:])
+
+		key = prf.Sum(key)
+		t := key[len(key)-size:]
+
+		copy(u, t)
+
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+
+	return key[:keyLen]
+}
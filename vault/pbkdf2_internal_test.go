@@ -0,0 +1,37 @@
+package vault
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Vectors are from RFC 7914, section 11.
+func TestPBKDF2(t *testing.T) {
+	tests := []struct {
+		password, salt string
+		iterations     int
+		expected       string
+	}{
+		{
+			password:   "passwd",
+			salt:       "salt",
+			iterations: 1,
+			expected: "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+				"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			password:   "Password",
+			salt:       "NaCl",
+			iterations: 80000,
+			expected: "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56" +
+				"a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+
+	for _, tt := range tests {
+		actual := hex.EncodeToString(pbkdf2([]byte(tt.password), []byte(tt.salt), tt.iterations, 64))
+		if actual != tt.expected {
+			t.Fatalf("Unexpected key for %s/%s. Actual: %s, expected: %s", tt.password, tt.salt, actual, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package vault
+
+import (
+	"context"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+// TokenSource gives the token stored under the name. The vault is read on every request,
+// so the token replaced or removed by another process is picked up without restarting clients.
+func (v *Vault) TokenSource(name string) mono.TokenSource {
+	return mono.TokenSourceFunc(func(context.Context) (string, error) {
+		return v.Get(name)
+	})
+}
@@ -0,0 +1,374 @@
+// Package vault keeps personal tokens in the file encrypted with the passphrase.
+//
+// The key is derived from the passphrase with PBKDF2-HMAC-SHA256, and tokens are sealed with AES-GCM:
+//
+//	v, err := vault.Open("tokens.vault", passphrase)
+//	if err != nil {
+//	  return err
+//	}
+//
+//	if err := v.Add("alice", token); err != nil {
+//	  return err
+//	}
+//
+//	personal, err := mono.NewPersonalWithTokenSource(v.TokenSource("alice"))
+//
+// The file is replaced atomically on every change. Processes sharing the file
+// see changes of each other, but concurrent writes may overwrite one another.
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Error is the vault error that can be compared with `errors.Is`.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNotFound is returned when there is no token with the name.
+	ErrNotFound Error = "token is not found"
+	// ErrWrongPassphrase is returned when the file cannot be decrypted with the passphrase.
+	ErrWrongPassphrase Error = "wrong passphrase or corrupted vault"
+)
+
+const (
+	// DefaultIterations is the PBKDF2 iteration count for new vaults.
+	DefaultIterations = 600_000
+	// MaxIterations is the largest iteration count the vault accepts,
+	// so the tampered file cannot make opening it run the KDF for hours.
+	MaxIterations = 10 * DefaultIterations
+)
+
+const (
+	version = 1
+	kdfName = "pbkdf2-sha256"
+	keySize = 32
+	saltLen = 16
+)
+
+// Option allows to change default values of the vault.
+type Option func(*Vault)
+
+// WithIterations sets the PBKDF2 iteration count used when the vault is created or its passphrase is rotated.
+// Existing vaults keep the count they were written with.
+func WithIterations(n int) Option {
+	return func(v *Vault) {
+		v.iterations = n
+	}
+}
+
+// Vault is the encrypted file of named tokens.
+type Vault struct {
+	path       string
+	iterations int
+
+	mu         sync.Mutex
+	passphrase []byte
+	keys       map[string][]byte
+}
+
+// file is the vault on disk. The header is authenticated together with the encrypted entries.
+type file struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// Open opens the vault at the path, checking the passphrase if the file exists.
+// The file is created on the first change.
+func Open(path, passphrase string, opts ...Option) (*Vault, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+
+	v := &Vault{path: path, iterations: DefaultIterations, passphrase: []byte(passphrase), keys: map[string][]byte{}}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if v.iterations < 1 || v.iterations > MaxIterations {
+		return nil, fmt.Errorf("iterations must be between 1 and %d", MaxIterations)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, _, err := v.read(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Add stores the token under the name, replacing the previous one.
+func (v *Vault) Add(name, token string) error {
+	if len(name) == 0 || len(token) == 0 {
+		return errors.New("name and token are required")
+	}
+
+	return v.update(func(entries map[string]string) error {
+		entries[name] = token
+		return nil
+	})
+}
+
+// Get returns the token stored under the name.
+func (v *Vault) Get(name string) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entries, _, err := v.read()
+	if err != nil {
+		return "", err
+	}
+
+	token, ok := entries[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	return token, nil
+}
+
+// List returns the names of stored tokens in order.
+func (v *Vault) List() ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entries, _, err := v.read()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Remove deletes the token stored under the name.
+func (v *Vault) Remove(name string) error {
+	return v.update(func(entries map[string]string) error {
+		if _, ok := entries[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+
+		delete(entries, name)
+
+		return nil
+	})
+}
+
+// RotatePassphrase encrypts the vault with the key derived from the new passphrase and the new salt.
+func (v *Vault) RotatePassphrase(passphrase string) error {
+	if len(passphrase) == 0 {
+		return errors.New("passphrase is required")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entries, _, err := v.read()
+	if err != nil {
+		return err
+	}
+
+	salt, err := random(saltLen)
+	if err != nil {
+		return err
+	}
+
+	// The new state is kept aside until the file is written, so the failed write leaves the vault usable.
+	header := file{Version: version, KDF: kdfName, Iterations: v.iterations, Salt: salt}
+	next := []byte(passphrase)
+	key := pbkdf2(next, salt, header.Iterations, keySize)
+
+	if err := v.write(entries, header, key); err != nil {
+		return err
+	}
+
+	v.passphrase = next
+	v.keys = map[string][]byte{header.keyID(): key}
+
+	return nil
+}
+
+func (v *Vault) update(change func(map[string]string) error) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entries, header, err := v.read()
+	if err != nil {
+		return err
+	}
+
+	if err := change(entries); err != nil {
+		return err
+	}
+
+	return v.write(entries, header, v.key(header))
+}
+
+// read decrypts the entries, returning the header to write them with.
+// The missing file is the empty vault with the new salt.
+func (v *Vault) read() (map[string]string, file, error) {
+	entries := map[string]string{}
+
+	bts, err := ioutil.ReadFile(v.path)
+	if os.IsNotExist(err) {
+		salt, err := random(saltLen)
+		return entries, file{Version: version, KDF: kdfName, Iterations: v.iterations, Salt: salt}, err
+	}
+
+	if err != nil {
+		return nil, file{}, fmt.Errorf("failed to read vault: %v", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(bts, &f); err != nil {
+		return nil, file{}, fmt.Errorf("failed to unmarshal vault: %v", err)
+	}
+
+	if f.Version != version || f.KDF != kdfName {
+		return nil, file{}, fmt.Errorf("unsupported vault version %d with %s", f.Version, f.KDF)
+	}
+
+	if f.Iterations < 1 || f.Iterations > MaxIterations {
+		return nil, file{}, fmt.Errorf("vault iterations %d are out of the range from 1 to %d", f.Iterations, MaxIterations)
+	}
+
+	aead, err := newAEAD(v.key(f))
+	if err != nil {
+		return nil, file{}, err
+	}
+
+	if len(f.Nonce) != aead.NonceSize() {
+		return nil, file{}, ErrWrongPassphrase
+	}
+
+	plain, err := aead.Open(nil, f.Nonce, f.Data, f.additionalData())
+	if err != nil {
+		return nil, file{}, ErrWrongPassphrase
+	}
+
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil, file{}, fmt.Errorf("failed to unmarshal vault: %v", err)
+	}
+
+	return entries, f, nil
+}
+
+// write seals the entries with the key and the new nonce, and replaces the file atomically.
+func (v *Vault) write(entries map[string]string, f file, key []byte) error {
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault: %v", err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	if f.Nonce, err = random(aead.NonceSize()); err != nil {
+		return err
+	}
+
+	f.Data = aead.Seal(nil, f.Nonce, plain, f.additionalData())
+
+	bts, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault: %v", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(v.path), ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write vault: %v", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(bts); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write vault: %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write vault: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), v.path); err != nil {
+		return fmt.Errorf("failed to write vault: %v", err)
+	}
+
+	return nil
+}
+
+// key derives the key from the passphrase for the salt of the file, remembering it,
+// so reading the unchanged vault again does not run the KDF.
+func (v *Vault) key(f file) []byte {
+	id := f.keyID()
+
+	key, ok := v.keys[id]
+	if !ok {
+		key = pbkdf2(v.passphrase, f.Salt, f.Iterations, keySize)
+		v.keys = map[string][]byte{id: key}
+	}
+
+	return key
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+
+	return aead, nil
+}
+
+func (f file) keyID() string {
+	return fmt.Sprintf("%d:%x", f.Iterations, f.Salt)
+}
+
+// additionalData binds the header to the ciphertext, so the KDF parameters cannot be swapped.
+func (f file) additionalData() []byte {
+	return []byte(fmt.Sprintf("%d|%s|%d|%x", f.Version, f.KDF, f.Iterations, f.Salt))
+}
+
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %v", err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,237 @@
+package vault_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kudrykv/go-monobank-api/vault"
+)
+
+func TestVault(t *testing.T) {
+	path, cleanup := vaultPath(t)
+	defer cleanup()
+
+	v, err := vault.Open(path, "secret", vault.WithIterations(10))
+	expectNoError(t, err)
+
+	names, err := v.List()
+	expectNoError(t, err)
+
+	if len(names) != 0 {
+		t.Fatalf("Expected the empty vault, got %v", names)
+	}
+
+	expectNoError(t, v.Add("bob", "token-b"))
+	expectNoError(t, v.Add("alice", "token-a"))
+	expectNoError(t, v.Add("bob", "token-b2"))
+
+	names, err = v.List()
+	expectNoError(t, err)
+
+	if !reflect.DeepEqual(names, []string{"alice", "bob"}) {
+		t.Fatalf("Unexpected names: %v", names)
+	}
+
+	bts, err := ioutil.ReadFile(path)
+	expectNoError(t, err)
+
+	if bytes.Contains(bts, []byte("token-")) || bytes.Contains(bts, []byte("alice")) {
+		t.Fatalf("Vault must not keep names and tokens in plain text: %s", bts)
+	}
+
+	reopened, err := vault.Open(path, "secret")
+	expectNoError(t, err)
+
+	token, err := reopened.Get("bob")
+	expectNoError(t, err)
+
+	if token != "token-b2" {
+		t.Fatalf("Unexpected token: %s", token)
+	}
+
+	expectNoError(t, reopened.Remove("bob"))
+
+	_, err = v.Get("bob")
+	if !errors.Is(err, vault.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := v.Remove("bob"); err == nil || err.Error() != "token is not found: bob" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestVault_RotatePassphrase(t *testing.T) {
+	path, cleanup := vaultPath(t)
+	defer cleanup()
+
+	v, err := vault.Open(path, "old", vault.WithIterations(10))
+	expectNoError(t, err)
+	expectNoError(t, v.Add("alice", "token-a"))
+	expectNoError(t, v.RotatePassphrase("new"))
+
+	_, err = vault.Open(path, "old")
+	if !errors.Is(err, vault.ErrWrongPassphrase) {
+		t.Fatalf("Expected ErrWrongPassphrase, got %v", err)
+	}
+
+	v, err = vault.Open(path, "new")
+	expectNoError(t, err)
+
+	token, err := v.Get("alice")
+	expectNoError(t, err)
+
+	if token != "token-a" {
+		t.Fatalf("Unexpected token: %s", token)
+	}
+}
+
+func TestVault_RotatePassphrase_WriteFailed(t *testing.T) {
+	path, cleanup := vaultPath(t)
+	defer cleanup()
+
+	v, err := vault.Open(path, "old", vault.WithIterations(10))
+	expectNoError(t, err)
+	expectNoError(t, v.Add("alice", "token-a"))
+
+	// Taking the directory away makes writing the rotated vault fail.
+	dir := filepath.Dir(path)
+	expectNoError(t, os.Rename(dir, dir+".away"))
+
+	err = v.RotatePassphrase("new")
+
+	expectNoError(t, os.Rename(dir+".away", dir))
+
+	if err == nil {
+		t.Fatal("Expected rotating to fail")
+	}
+
+	token, err := v.Get("alice")
+	expectNoError(t, err)
+
+	if token != "token-a" {
+		t.Fatalf("Unexpected token: %s", token)
+	}
+
+	_, err = vault.Open(path, "old")
+	expectNoError(t, err)
+}
+
+func TestVault_Tampered(t *testing.T) {
+	path, cleanup := vaultPath(t)
+	defer cleanup()
+
+	v, err := vault.Open(path, "secret", vault.WithIterations(10))
+	expectNoError(t, err)
+	expectNoError(t, v.Add("alice", "token-a"))
+
+	bts, err := ioutil.ReadFile(path)
+	expectNoError(t, err)
+
+	// Lowering the iteration count must not pass, as the header is authenticated.
+	tampered := bytes.Replace(bts, []byte(`"iterations":10`), []byte(`"iterations":1`), 1)
+	expectNoError(t, ioutil.WriteFile(path, tampered, 0o600))
+
+	_, err = vault.Open(path, "secret")
+	if !errors.Is(err, vault.ErrWrongPassphrase) {
+		t.Fatalf("Expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestVault_TokenSource(t *testing.T) {
+	path, cleanup := vaultPath(t)
+	defer cleanup()
+
+	v, err := vault.Open(path, "secret", vault.WithIterations(10))
+	expectNoError(t, err)
+	expectNoError(t, v.Add("alice", "token-a"))
+
+	source := v.TokenSource("alice")
+
+	token, err := source.Token(context.Background())
+	expectNoError(t, err)
+
+	if token != "token-a" {
+		t.Fatalf("Unexpected token: %s", token)
+	}
+
+	other, err := vault.Open(path, "secret")
+	expectNoError(t, err)
+	expectNoError(t, other.Add("alice", "token-a2"))
+
+	if token, _ = source.Token(context.Background()); token != "token-a2" {
+		t.Fatalf("Source must pick up the replaced token, got %s", token)
+	}
+
+	expectNoError(t, other.Remove("alice"))
+
+	if _, err = source.Token(context.Background()); !errors.Is(err, vault.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestVault_TamperedIterations(t *testing.T) {
+	path, cleanup := vaultPath(t)
+	defer cleanup()
+
+	v, err := vault.Open(path, "secret", vault.WithIterations(10))
+	expectNoError(t, err)
+	expectNoError(t, v.Add("alice", "token-a"))
+
+	bts, err := ioutil.ReadFile(path)
+	expectNoError(t, err)
+
+	tampered := bytes.Replace(bts, []byte(`"iterations":10`), []byte(`"iterations":1000000000000`), 1)
+	expectNoError(t, ioutil.WriteFile(path, tampered, 0o600))
+
+	_, err = vault.Open(path, "secret")
+	if err == nil || err.Error() != "vault iterations 1000000000000 are out of the range from 1 to 6000000" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestOpen_Fail(t *testing.T) {
+	if _, err := vault.Open("unused", ""); err == nil || err.Error() != "passphrase is required" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := vault.Open("unused", "secret", vault.WithIterations(0)); err == nil {
+		t.Fatal("Expected the error for zero iterations")
+	}
+
+	if _, err := vault.Open("unused", "secret", vault.WithIterations(vault.MaxIterations+1)); err == nil {
+		t.Fatal("Expected the error for too many iterations")
+	}
+
+	path, cleanup := vaultPath(t)
+	defer cleanup()
+
+	expectNoError(t, ioutil.WriteFile(path, []byte("not json"), 0o600))
+
+	if _, err := vault.Open(path, "secret"); err == nil {
+		t.Fatal("Expected the error for the corrupted file")
+	}
+}
+
+func vaultPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "vault")
+	expectNoError(t, err)
+
+	return filepath.Join(dir, "tokens.vault"), func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func expectNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("No error expected, got: %v", err)
+	}
+}
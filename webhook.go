@@ -0,0 +1,65 @@
+package mono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ValidateWebhookURL checks the webhook URL before it is sent to the bank.
+// The URL must be absolute, use https scheme, have no fragment,
+// and be no longer than `MaxWebhookURLLength`.
+func ValidateWebhookURL(webhook string) error {
+	if len(webhook) == 0 {
+		return errors.New("webhook must be set")
+	}
+
+	if len(webhook) > MaxWebhookURLLength {
+		return errors.New("webhook must be at most " + strconv.Itoa(MaxWebhookURLLength) + " characters long")
+	}
+
+	if strings.Contains(webhook, "#") {
+		return errors.New("webhook must not have a fragment")
+	}
+
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return fmt.Errorf("failed to parse webhook: %v", err)
+	}
+
+	if u.Scheme != "https" {
+		return errors.New("webhook must use https scheme")
+	}
+
+	if len(u.Host) == 0 {
+		return errors.New("webhook must have a host")
+	}
+
+	return nil
+}
+
+func (c tinyClient) handshake(ctx context.Context, webhook string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, webhook, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make handshake: %v", err)
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		return fmt.Errorf("failed to close the body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("webhook answered handshake with " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return nil
+}
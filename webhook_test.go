@@ -0,0 +1,26 @@
+package mono_test
+
+import (
+	"strings"
+	"testing"
+
+	mono "github.com/kudrykv/go-monobank-api"
+)
+
+func TestValidateWebhookURL_Succ(t *testing.T) {
+	expectNoError(t, mono.ValidateWebhookURL("https://domain/webhook"))
+	expectNoError(t, mono.ValidateWebhookURL("https://domain:8443/webhook?token=abc"))
+}
+
+func TestValidateWebhookURL_Fail(t *testing.T) {
+	expectError(t, mono.ValidateWebhookURL(""), "webhook must be set")
+	expectError(t, mono.ValidateWebhookURL("http://domain/webhook"), "webhook must use https scheme")
+	expectError(t, mono.ValidateWebhookURL("/webhook"), "webhook must use https scheme")
+	expectError(t, mono.ValidateWebhookURL("https:///webhook"), "webhook must have a host")
+	expectError(t, mono.ValidateWebhookURL("https://domain/webhook#frag"), "webhook must not have a fragment")
+	expectError(t, mono.ValidateWebhookURL("https://domain/webhook#"), "webhook must not have a fragment")
+	expectErrorStartsWith(t, mono.ValidateWebhookURL("https://domain:port/webhook"), "failed to parse webhook: ")
+
+	long := "https://domain/" + strings.Repeat("a", mono.MaxWebhookURLLength)
+	expectError(t, mono.ValidateWebhookURL(long), "webhook must be at most 2048 characters long")
+}